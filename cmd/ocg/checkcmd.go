@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ttd2089/ocg/internal/opts"
+)
+
+// The exit codes of the check command.
+const (
+	checkExitSynced     = 0
+	checkExitUnfinished = 1
+	checkExitError      = 2
+)
+
+func newCheckCmd(appCtx appContext) cmd {
+	return &checkCmd{
+		discovery: newDiscoveryOpts(),
+		jobsOpt:   newJobsOpt(),
+		appCtx:    appCtx,
+	}
+}
+
+type checkCmd struct {
+	discovery *discoveryOpts
+	jobsOpt   opts.IntOpt
+	appCtx    appContext
+}
+
+func (c *checkCmd) declare() cmdDecl {
+	return cmdDecl{
+		name:    "check",
+		summary: "Exit with a non-zero status if any git repository has unfinished work",
+		description: []string{
+			"Checks every repository for unfinished work and exits with a status describing the result. Each piece of unfinished work is printed to stderr as '<path>: <reason>'.",
+			"Unfinished work is an operation in progress, uncommitted changes, stashed changes, a detached HEAD that is not on any branch, or a branch that does not track a remote branch, is ahead of or behind the branch it tracks, or is not merged into the default branch of its remote. Branches of bare repos without remotes are not expected to track a remote branch.",
+		},
+		args: []argDecl{dirsArgDecl},
+		options: []optionDecl{
+			jobsOptDecl(&c.jobsOpt, "inspect"),
+		},
+		optionGroups: []optionGroup{c.discovery.group()},
+		sections: []helpSection{
+			{
+				title: "exit status",
+				lines: []string{
+					"  0    Every repository is synced",
+					"  1    Unfinished work was found",
+					"  2    An error occurred",
+				},
+			},
+		},
+		usageStatus: checkExitError,
+	}
+}
+
+func (c *checkCmd) run(args []string) int {
+
+	summaries, err := summarizeRepos(c.discovery.search(c.appCtx, args), c.appCtx.gitCLI, c.jobsOpt.Value, summaryOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return checkExitError
+	}
+
+	unfinished := false
+	output := new(bytes.Buffer)
+	for _, summary := range summaries {
+		for _, reason := range summary.unfinishedWork() {
+			fmt.Fprintf(output, "%s: %s\n", summary.Path, reason)
+			unfinished = true
+		}
+	}
+
+	io.Copy(os.Stderr, output)
+	if unfinished {
+		return checkExitUnfinished
+	}
+	return checkExitSynced
+}
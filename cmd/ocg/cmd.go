@@ -1,12 +1,277 @@
 package main
 
-import "github.com/ttd2089/shgit"
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ttd2089/ocg/internal/config"
+	"github.com/ttd2089/ocg/internal/opts"
+	"github.com/ttd2089/shgit"
+)
 
 type appContext struct {
 	wd     string
 	gitCLI shgit.CLI
+	config config.Config
+
+	// configErr is the error encountered loading the config file, if any. The config is empty
+	// when there is an error, which is only reported by the commands that use the config.
+	configErr error
 }
 
+// A cmd is an ocg command.
 type cmd interface {
+
+	// declare returns the declaration of the command. The declared options are bound to the
+	// command's fields so parsing them sets the values that run uses.
+	declare() cmdDecl
+
+	// run runs the command with the arguments that remain once its options have been parsed and
+	// validated.
 	run(args []string) int
 }
+
+// commands returns the constructors of the ocg commands in the order they're listed in help.
+func commands() []func(appContext) cmd {
+	return []func(appContext) cmd{
+		newCheckCmd,
+		newCompletionCmd,
+		newFetchCmd,
+		newListCmd,
+		newPruneCmd,
+		newPushCmd,
+		newStatusCmd,
+		newSyncCmd,
+		newHelpCmd,
+		newVersionCmd,
+	}
+}
+
+// findCmd returns the command with the given name, or nil if there is no such command.
+func findCmd(appCtx appContext, name string) cmd {
+	for _, newCmd := range commands() {
+		command := newCmd(appCtx)
+		if command.declare().name == name {
+			return command
+		}
+	}
+	return nil
+}
+
+// A cmdDecl declares a command's name, arguments and options along with the text that describes
+// them in help.
+type cmdDecl struct {
+
+	// name is the name the command is invoked by.
+	name string
+
+	// summary is the one line description of the command listed by ocg help.
+	summary string
+
+	// description holds the paragraphs describing the command in its help text.
+	description []string
+
+	// args are the positional arguments the command accepts. Required arguments must precede
+	// optional ones.
+	args []argDecl
+
+	// options are the command's general options.
+	options []optionDecl
+
+	// optionGroups are the command's options that are listed under their own headings.
+	optionGroups []optionGroup
+
+	// sections are additional sections of preformatted help text, e.g. tables of symbols.
+	sections []helpSection
+
+	// usageStatus is the exit status for invalid options and arguments, and for a config file
+	// that can't be loaded. Zero means 1.
+	usageStatus int
+
+	// ignoresConfig indicates whether the command runs when the config file can't be loaded.
+	ignoresConfig bool
+}
+
+// An argDecl declares a positional argument.
+type argDecl struct {
+	name string
+	help string
+
+	// required indicates whether the argument must be given.
+	required bool
+
+	// repeated indicates whether the argument may be given any number of times.
+	repeated bool
+
+	// values are the values the argument accepts, or empty if it accepts any value.
+	values []string
+
+	// complete returns the candidates for completing the argument. It may be nil, in which case
+	// the accepted values are completed.
+	complete completer
+}
+
+// accepts returns a bool indicating whether value is one of the values the argument accepts.
+func (a argDecl) accepts(value string) bool {
+	if len(a.values) == 0 {
+		return true
+	}
+	for _, v := range a.values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// An optionDecl declares an option.
+type optionDecl struct {
+	opt opts.NamedOption
+
+	// value is the placeholder for the option's value in help, e.g. <n>, or empty for flags.
+	value string
+
+	help string
+
+	// validate returns an error if the parsed value of the option is invalid. It may be nil.
+	validate func() error
+
+	// complete returns the candidates for completing the option's value. It may be nil, in which
+	// case the values of an EnumOpt are completed.
+	complete completer
+}
+
+// An optionGroup is a set of related options listed under their own heading in help.
+type optionGroup struct {
+	title   string
+	options []optionDecl
+
+	// notes holds paragraphs printed after the options.
+	notes []string
+}
+
+// A helpSection is a section of preformatted help text.
+type helpSection struct {
+	title string
+	lines []string
+}
+
+// allOptions returns every option declared by the command.
+func (d cmdDecl) allOptions() []optionDecl {
+	options := append([]optionDecl{}, d.options...)
+	for _, group := range d.optionGroups {
+		options = append(options, group.options...)
+	}
+	return options
+}
+
+// validate returns an error if args has missing, unexpected or invalid arguments, or any of the
+// parsed options has an invalid value.
+func (d cmdDecl) validate(args []string) error {
+	repeated := len(d.args) > 0 && d.args[len(d.args)-1].repeated
+	if !repeated && len(args) > len(d.args) {
+		return fmt.Errorf("unexpected argument '%s'", args[len(d.args)])
+	}
+	for i, value := range args {
+		arg := d.args[len(d.args)-1]
+		if i < len(d.args) {
+			arg = d.args[i]
+		}
+		if !arg.accepts(value) {
+			return fmt.Errorf(
+				"invalid value '%s' for argument '%s': must be one of %s",
+				value,
+				arg.name,
+				strings.Join(arg.values, ", "))
+		}
+	}
+	for i := len(args); i < len(d.args); i++ {
+		if d.args[i].required {
+			return fmt.Errorf("missing argument '%s'", d.args[i].name)
+		}
+	}
+	for _, option := range d.allOptions() {
+		if option.validate == nil {
+			continue
+		}
+		if err := option.validate(); err != nil {
+			if bound, ok := option.opt.(*opts.BoundOption); ok {
+				return bound.Annotate(err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve sets the values of the bound options that weren't given on the command line from their
+// environment variables or the config file.
+func (d cmdDecl) resolve(appCtx appContext) error {
+	for _, option := range d.allOptions() {
+		bound, ok := option.opt.(*opts.BoundOption)
+		if !ok {
+			continue
+		}
+		if err := bound.Resolve(os.LookupEnv, appCtx.config.Lookup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// helpOptDecl declares opt as the -h/--help option that every command accepts.
+func helpOptDecl(opt *opts.FlagOpt) optionDecl {
+	opt.OptionName = opts.OptionName{
+		LongName:  "help",
+		ShortName: 'h',
+	}
+	return optionDecl{
+		opt:  opt,
+		help: "Print help text",
+	}
+}
+
+// runCmd parses args using the options declared by command and runs it with the remaining
+// arguments. Options may be given before, between or after the arguments. Bound options that
+// aren't given in args are set from their environment variables or the config file. Help is printed
+// instead when it's requested, and along with an error when the options or arguments are invalid.
+func runCmd(appCtx appContext, command cmd, args []string) int {
+	var helpOpt opts.FlagOpt
+	decl := command.declare()
+	decl.options = append([]optionDecl{helpOptDecl(&helpOpt)}, decl.options...)
+
+	var parseable []opts.Option
+	for _, option := range decl.allOptions() {
+		parseable = append(parseable, option.opt)
+	}
+	args, err := opts.ParseInterleaved(args, parseable)
+	if err == nil && !helpOpt.Value && !decl.ignoresConfig && appCtx.configErr != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", appCtx.configErr)
+		return decl.errorStatus()
+	}
+	if err == nil {
+		err = decl.resolve(appCtx)
+	}
+	if err == nil && helpOpt.Value {
+		writeHelp(os.Stdout, cmdHelpText(decl))
+		return 0
+	}
+	if err == nil {
+		err = decl.validate(args)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n\n", err)
+		writeHelp(os.Stderr, cmdHelpText(decl))
+		return decl.errorStatus()
+	}
+	return command.run(args)
+}
+
+// errorStatus returns the exit status for invalid options and arguments.
+func (d cmdDecl) errorStatus() int {
+	if d.usageStatus != 0 {
+		return d.usageStatus
+	}
+	return 1
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// A color is an ANSI SGR color code.
+type color int
+
+const (
+	colorRed     color = 31
+	colorGreen   color = 32
+	colorYellow  color = 33
+	colorBlue    color = 34
+	colorMagenta color = 35
+	colorCyan    color = 36
+)
+
+// A colorizer wraps text in ANSI color escape sequences when enabled.
+type colorizer struct {
+	enabled bool
+}
+
+// newColorizer returns a colorizer that is enabled when f is a terminal and the NO_COLOR
+// environment variable is not set (https://no-color.org).
+func newColorizer(f *os.File) colorizer {
+	if _, noColor := os.LookupEnv("NO_COLOR"); noColor {
+		return colorizer{}
+	}
+	return colorizer{enabled: isTerminal(f)}
+}
+
+func (c colorizer) paint(col color, s string) string {
+	if !c.enabled {
+		return s
+	}
+	return fmt.Sprintf("\x1b[%dm%s\x1b[0m", col, s)
+}
+
+// isTerminal returns a bool indicating whether f is a character device, e.g. a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
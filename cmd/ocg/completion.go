@@ -0,0 +1,319 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ttd2089/ocg/internal/git"
+	"github.com/ttd2089/ocg/internal/opts"
+)
+
+// completeCmdName is the name of the hidden command that the completion scripts run to complete
+// the word under the cursor.
+const completeCmdName = "__complete"
+
+// A completer returns the candidates for completing word. The candidates are filtered by prefix
+// after they're returned.
+type completer func(appCtx appContext, word string) []string
+
+// completeWords returns the candidates for completing the last of words, which are the arguments
+// typed after ocg. The last word is empty when the cursor is not in a word.
+func completeWords(appCtx appContext, words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	word := words[len(words)-1]
+	words = words[:len(words)-1]
+
+	// The top-level options are all flags so the first word that isn't an option is the command.
+	for len(words) > 0 && strings.HasPrefix(words[0], "-") {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		if strings.HasPrefix(word, "-") {
+			ocgOpts := newOCGOptions()
+			return filterPrefix(optionNames(ocgOpts.declare()), word)
+		}
+		return filterPrefix(completeCommands(appCtx, word), word)
+	}
+
+	command := findCmd(appCtx, words[0])
+	if command == nil {
+		return nil
+	}
+	var helpOpt opts.FlagOpt
+	decl := command.declare()
+	decl.options = append([]optionDecl{helpOptDecl(&helpOpt)}, decl.options...)
+	options := decl.allOptions()
+
+	// Skip over the options and their values to find out how many positional arguments precede
+	// the word and whether it's the value of an option.
+	var valueOf *optionDecl
+	positional := 0
+	parsingOptions := true
+	for _, arg := range words[1:] {
+		switch {
+		case valueOf != nil:
+			valueOf = nil
+		case !parsingOptions:
+			positional++
+		case arg == "--":
+			parsingOptions = false
+		case strings.HasPrefix(arg, "--"):
+			if !strings.Contains(arg, "=") {
+				valueOf = findOption(options, arg[2:], 0)
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			// In a cluster of short options only the last may take its value from the next arg.
+			for i, short := range arg[1:] {
+				if option := findOption(options, "", short); option != nil && option.value != "" {
+					if i == len(arg)-2 {
+						valueOf = option
+					}
+					break
+				}
+			}
+		default:
+			positional++
+		}
+		if valueOf != nil && valueOf.value == "" {
+			valueOf = nil
+		}
+	}
+
+	switch {
+	case valueOf != nil:
+		return filterPrefix(completeValue(appCtx, *valueOf, word), word)
+	case parsingOptions && strings.HasPrefix(word, "--") && strings.Contains(word, "="):
+		name, value, _ := strings.Cut(word[2:], "=")
+		option := findOption(options, name, 0)
+		if option == nil || option.value == "" {
+			return nil
+		}
+		var candidates []string
+		for _, candidate := range completeValue(appCtx, *option, value) {
+			candidates = append(candidates, "--"+name+"="+candidate)
+		}
+		return filterPrefix(candidates, word)
+	case parsingOptions && strings.HasPrefix(word, "-"):
+		return filterPrefix(optionNames(options), word)
+	}
+
+	if len(decl.args) == 0 {
+		return nil
+	}
+	if positional >= len(decl.args) {
+		if !decl.args[len(decl.args)-1].repeated {
+			return nil
+		}
+		positional = len(decl.args) - 1
+	}
+	arg := decl.args[positional]
+	if arg.complete == nil {
+		return filterPrefix(arg.values, word)
+	}
+	return filterPrefix(arg.complete(appCtx, word), word)
+}
+
+// findOption returns the declaration of the option with the given long or short name, or nil if
+// there is no such option.
+func findOption(options []optionDecl, longName string, shortName rune) *optionDecl {
+	for i, option := range options {
+		name := option.opt.Name()
+		if (longName != "" && name.LongName == longName) || (shortName != 0 && name.ShortName == shortName) {
+			return &options[i]
+		}
+	}
+	return nil
+}
+
+// optionNames returns the long and short forms of the names of options.
+func optionNames(options []optionDecl) []string {
+	var names []string
+	for _, option := range options {
+		name := option.opt.Name()
+		if name.LongName != "" {
+			names = append(names, "--"+name.LongName)
+		}
+		if name.ShortName != 0 {
+			names = append(names, "-"+string(name.ShortName))
+		}
+	}
+	return names
+}
+
+// completeValue returns the candidates for the value of option. The values of an EnumOpt are
+// completed unless the option declares its own completer.
+func completeValue(appCtx appContext, option optionDecl, word string) []string {
+	if option.complete != nil {
+		return option.complete(appCtx, word)
+	}
+	opt := option.opt
+	if bound, ok := opt.(*opts.BoundOption); ok {
+		opt = bound.NamedOption
+	}
+	if enum, ok := opt.(*opts.EnumOpt); ok {
+		return enum.Values
+	}
+	return nil
+}
+
+// filterPrefix returns the candidates that start with prefix.
+func filterPrefix(candidates []string, prefix string) []string {
+	var filtered []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			filtered = append(filtered, candidate)
+		}
+	}
+	return filtered
+}
+
+// completeCommands completes the names of the ocg commands.
+func completeCommands(appCtx appContext, word string) []string {
+	var names []string
+	for _, newCmd := range commands() {
+		names = append(names, newCmd(appCtx).declare().name)
+	}
+	return names
+}
+
+// completeDirs completes the paths of directories. Directory candidates end with a separator so
+// the path can be completed further.
+func completeDirs(appCtx appContext, word string) []string {
+	dir, prefix := filepath.Split(word)
+	parent := resolveDir(appCtx, expandHome(dir))
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".") {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(parent, name))
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		dirs = append(dirs, dir+name+string(filepath.Separator))
+	}
+	return dirs
+}
+
+// completeRepos completes the paths of the repos found under the configured roots as well as the
+// paths of directories. Repo paths are written the way word is: relative to the working directory,
+// relative to the home directory with a leading ~, or absolute.
+func completeRepos(appCtx appContext, word string) []string {
+	var candidates []string
+	for _, path := range discoverRepoPaths(appCtx) {
+		if candidate := displayPath(appCtx, word, path); candidate != "" {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return append(completeDirs(appCtx, word), candidates...)
+}
+
+// completeRepoNames completes the names of the repos found under the configured roots.
+func completeRepoNames(appCtx appContext, word string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, path := range discoverRepoPaths(appCtx) {
+		name := filepath.Base(path)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completeBranches completes the names of the local branches of the repos found under the
+// configured roots.
+func completeBranches(appCtx appContext, word string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, path := range discoverRepoPaths(appCtx) {
+		repo, err := git.NewRepo(path, appCtx.gitCLI)
+		if err != nil {
+			continue
+		}
+		branches, err := repo.LocalBranches()
+		if err != nil {
+			continue
+		}
+		for _, branch := range branches {
+			if !seen[branch.Name] {
+				seen[branch.Name] = true
+				names = append(names, branch.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// discoverRepoPaths returns the paths of the repos found under the configured roots, or the
+// working directory if there are none. Errors are ignored since there's nowhere to report them
+// during completion.
+func discoverRepoPaths(appCtx appContext) []string {
+	var paths []string
+	search := newDiscoveryOpts().search(appCtx, nil)
+	_ = walkRepos(search, appCtx.gitCLI, func(root string, repo git.Repo) error {
+		paths = append(paths, repo.Path())
+		return nil
+	})
+	sort.Strings(paths)
+	return paths
+}
+
+// displayPath returns the absolute path written the way word is, or an empty string when path is
+// the working directory.
+func displayPath(appCtx appContext, word, path string) string {
+	if strings.HasPrefix(word, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		if rel, ok := relativePath(home, path); ok {
+			return filepath.Join("~", rel)
+		}
+		return ""
+	}
+	if filepath.IsAbs(word) {
+		return path
+	}
+	rel, ok := relativePath(appCtx.wd, path)
+	if !ok {
+		return path
+	}
+	if rel == "." {
+		return ""
+	}
+	return rel
+}
+
+// relativePath returns path relative to base and a bool indicating whether path is within base.
+func relativePath(base, path string) (string, bool) {
+	rel, err := filepath.Rel(base, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// expandHome replaces a leading ~ in path with the home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
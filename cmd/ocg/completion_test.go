@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ttd2089/shgit"
+)
+
+func TestCompleteWords(t *testing.T) {
+
+	// The working directory holds a single directory, which is what the <dir> arguments complete
+	// to, so the tests can tell when a word is completed as a positional argument.
+	wd := t.TempDir()
+	if err := os.Mkdir(filepath.Join(wd, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	appCtx := appContext{
+		wd:     wd,
+		gitCLI: shgit.NewCLI(),
+	}
+	dirs := []string{"sub" + string(filepath.Separator)}
+
+	tests := []struct {
+		name     string
+		words    []string
+		expected []string
+	}{
+		{
+			name:     "Completes commands",
+			words:    []string{"s"},
+			expected: []string{"status", "sync"},
+		},
+		{
+			name:     "Completes commands after top-level options",
+			words:    []string{"--help", "ver"},
+			expected: []string{"version"},
+		},
+		{
+			name:     "Completes top-level options",
+			words:    []string{"--"},
+			expected: []string{"--help", "--version"},
+		},
+		{
+			name:     "Completes nothing for unknown commands",
+			words:    []string{"nope", ""},
+			expected: nil,
+		},
+		{
+			name:     "Completes command options",
+			words:    []string{"completion", "-"},
+			expected: []string{"--help", "-h"},
+		},
+		{
+			name:     "Completes argument values",
+			words:    []string{"completion", ""},
+			expected: []string{"bash", "zsh", "fish"},
+		},
+		{
+			name:     "Completes nothing after the last argument",
+			words:    []string{"completion", "bash", ""},
+			expected: nil,
+		},
+		{
+			name:     "Completes repeated arguments",
+			words:    []string{"push", "sub", ""},
+			expected: dirs,
+		},
+		{
+			name:     "Completes the value of a long option given in the next word",
+			words:    []string{"list", "--format", "j"},
+			expected: []string{"json"},
+		},
+		{
+			name:     "Completes the value of a short option given in the next word",
+			words:    []string{"list", "-f", ""},
+			expected: []string{"yaml", "json", "ndjson"},
+		},
+		{
+			name:     "Completes the value of a long option given after an equals sign",
+			words:    []string{"list", "--format=n"},
+			expected: []string{"--format=ndjson"},
+		},
+		{
+			name:     "Completes nothing after an equals sign for flags",
+			words:    []string{"list", "--files="},
+			expected: nil,
+		},
+		{
+			name:     "Completes nothing for the value of an option without candidates",
+			words:    []string{"push", "--jobs", ""},
+			expected: nil,
+		},
+		{
+			name:     "Completes arguments after option values",
+			words:    []string{"push", "--jobs", "4", ""},
+			expected: dirs,
+		},
+		{
+			name:     "Completes arguments after option values given after an equals sign",
+			words:    []string{"push", "--jobs=4", ""},
+			expected: dirs,
+		},
+		{
+			name:     "Completes arguments after flags",
+			words:    []string{"push", "--dry-run", ""},
+			expected: dirs,
+		},
+		{
+			name:     "Completes the value of the last option in a cluster",
+			words:    []string{"list", "-hf", ""},
+			expected: []string{"yaml", "json", "ndjson"},
+		},
+		{
+			name:     "Completes arguments after a cluster whose value is attached",
+			words:    []string{"list", "-fj", ""},
+			expected: dirs,
+		},
+		{
+			name:     "Completes arguments after a cluster ending in a flag",
+			words:    []string{"push", "-ni", ""},
+			expected: dirs,
+		},
+		{
+			name:     "Completes options after arguments",
+			words:    []string{"completion", "bash", "--h"},
+			expected: []string{"--help"},
+		},
+		{
+			name:     "Completes arguments rather than options after --",
+			words:    []string{"completion", "--", "-"},
+			expected: nil,
+		},
+		{
+			name:     "Counts words after -- as arguments",
+			words:    []string{"completion", "--", "-h", ""},
+			expected: nil,
+		},
+		{
+			name:     "Completes argument values after --",
+			words:    []string{"completion", "--", "b"},
+			expected: []string{"bash"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := completeWords(appCtx, tt.words)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %q; got %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// completionShells are the shells that ocg completion can print a script for.
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// completionScripts holds the completion script for each shell keyed by the name of the shell. The
+// scripts pass the words typed so far to ocg __complete which prints the candidates for the last
+// one, so they don't need to change when commands and options do.
+var completionScripts = map[string]string{
+	"bash": `# bash completion for ocg, generated by ocg completion bash.
+_ocg() {
+    local line="${COMP_LINE:0:COMP_POINT}"
+    local -a words
+    read -ra words <<< "$line"
+    if [[ -z $line || $line == *[[:space:]] ]]; then
+        words+=("")
+    fi
+    local cur="${words[${#words[@]}-1]}"
+    local IFS=$'\n'
+    COMPREPLY=($(ocg __complete "${words[@]:1}" 2>/dev/null))
+    # Bash breaks words at characters like = and only replaces the part after the last break.
+    local prefix="${cur%"${COMP_WORDS[COMP_CWORD]}"}"
+    COMPREPLY=("${COMPREPLY[@]#"$prefix"}")
+    if [[ ${#COMPREPLY[@]} -eq 1 && ${COMPREPLY[0]} == */ ]]; then
+        compopt -o nospace
+    fi
+}
+complete -F _ocg ocg
+`,
+	"zsh": `#compdef ocg
+# zsh completion for ocg, generated by ocg completion zsh.
+_ocg() {
+    local -a candidates dirs others
+    local candidate
+    candidates=("${(@f)$(ocg __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    for candidate in "${candidates[@]}"; do
+        if [[ -z $candidate ]]; then
+            continue
+        elif [[ $candidate == */ ]]; then
+            dirs+=("$candidate")
+        else
+            others+=("$candidate")
+        fi
+    done
+    compadd -Q -S '' -a dirs
+    compadd -Q -a others
+}
+if [[ $zsh_eval_context[-1] == loadautofunc ]]; then
+    _ocg "$@"
+else
+    compdef _ocg ocg
+fi
+`,
+	"fish": `# fish completion for ocg, generated by ocg completion fish.
+function __ocg_complete
+    set -l tokens (commandline -opc)
+    set -l current (commandline -ct)
+    ocg __complete $tokens[2..-1] "$current" 2>/dev/null
+end
+complete -c ocg -f -a '(__ocg_complete)'
+`,
+}
+
+func newCompletionCmd(appCtx appContext) cmd {
+	return &completionCmd{}
+}
+
+type completionCmd struct{}
+
+func (c *completionCmd) declare() cmdDecl {
+	return cmdDecl{
+		name:          "completion",
+		summary:       "Print a shell completion script",
+		ignoresConfig: true,
+		description: []string{
+			"Prints a script that completes ocg commands, options and their values for <shell>. Directory arguments are completed with the paths of the repositories found under the roots in the config file as well as directories, and --include is completed with the names of those repositories.",
+		},
+		args: []argDecl{
+			{
+				name:     "shell",
+				help:     fmt.Sprintf("The shell to print the script for: %s", strings.Join(completionShells, ", ")),
+				required: true,
+				values:   completionShells,
+			},
+		},
+		sections: []helpSection{
+			{
+				title: "installation",
+				lines: formatTable([][2]string{
+					{"bash", "Add 'source <(ocg completion bash)' to ~/.bashrc"},
+					{"zsh", "Add 'source <(ocg completion zsh)' to ~/.zshrc after compinit is run"},
+					{"fish", "Run 'ocg completion fish > ~/.config/fish/completions/ocg.fish'"},
+				}),
+			},
+		},
+	}
+}
+
+func (c *completionCmd) run(args []string) int {
+	fmt.Fprint(os.Stdout, completionScripts[args[0]])
+	return 0
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package main
+
+import (
+	"io/fs"
+)
+
+// newDirID returns the dirID of the directory at path. Platforms without device and inode numbers
+// identify directories by their path with symlinks resolved.
+func newDirID(path string, info fs.FileInfo) dirID {
+	return dirIDFromPath(path)
+}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package main
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// newDirID returns the dirID of the directory at path, identified by its device and inode numbers.
+func newDirID(path string, info fs.FileInfo) dirID {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return dirID{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}
+	}
+	return dirIDFromPath(path)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ttd2089/ocg/internal/ignore"
+	"github.com/ttd2089/ocg/internal/opts"
+)
+
+// discoveryOpts are the options that control where a command searches for repos.
+type discoveryOpts struct {
+	excludeOpt        opts.StringSliceOpt
+	followSymlinksOpt opts.FlagOpt
+	includeOpt        opts.StringSliceOpt
+	maxDepthOpt       opts.IntOpt
+}
+
+func newDiscoveryOpts() *discoveryOpts {
+	return &discoveryOpts{
+		excludeOpt: opts.StringSliceOpt{
+			OptionName: opts.OptionName{
+				LongName: "exclude",
+			},
+		},
+		followSymlinksOpt: newLongFlagOpt("follow-symlinks"),
+		includeOpt: opts.StringSliceOpt{
+			OptionName: opts.OptionName{
+				LongName: "include",
+			},
+		},
+		maxDepthOpt: opts.IntOpt{
+			OptionName: opts.OptionName{
+				LongName: "max-depth",
+			},
+		},
+	}
+}
+
+// group returns the declarations of the discovery options.
+func (d *discoveryOpts) group() optionGroup {
+	return optionGroup{
+		title: "discovery",
+		options: []optionDecl{
+			{
+				opt:      &d.excludeOpt,
+				value:    "<glob>",
+				help:     "Don't search directories matching glob; may be repeated",
+				validate: func() error { return validateGlobs(d.excludeOpt) },
+				complete: completeDirs,
+			},
+			{
+				opt:  opts.Bind(&d.followSymlinksOpt, "OCG_FOLLOW_SYMLINKS", "follow_symlinks"),
+				help: "Search symlinks to directories; each directory is only searched once however many paths lead to it",
+			},
+			{
+				opt:      &d.includeOpt,
+				value:    "<glob>",
+				help:     "Only include repos matching glob; may be repeated",
+				validate: func() error { return validateGlobs(d.includeOpt) },
+				complete: completeRepoNames,
+			},
+			{
+				opt:   opts.Bind(&d.maxDepthOpt, "OCG_MAX_DEPTH", "max_depth"),
+				value: "<n>",
+				help:  "The maximum depth below each <dir> to search for repos (defaults to 0, which means no limit)",
+				validate: func() error {
+					if d.maxDepthOpt.Value < 0 {
+						return opts.NewInvalidOptionValueHelpText("max-depth", fmt.Sprint(d.maxDepthOpt.Value), "must not be negative")
+					}
+					return nil
+				},
+			},
+		},
+		notes: []string{
+			"Globs containing a / match the whole path and other globs match the directory name. Directories are also skipped when they match a pattern in a .ocgignore file, which uses the gitignore syntax and applies to the directory containing it and its descendants.",
+		},
+	}
+}
+
+// validateGlobs returns an error if any of the values of opt is not a valid glob.
+func validateGlobs(opt opts.StringSliceOpt) error {
+	for _, pattern := range opt.Value {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return opts.NewInvalidOptionValueHelpText(opt.LongName, pattern, "must be a valid glob")
+		}
+	}
+	return nil
+}
+
+// search returns a repoSearch for the optional <dir>... arguments. When no directories are given
+// the roots from the config file are searched, falling back to the working directory.
+func (d *discoveryOpts) search(appCtx appContext, args []string) repoSearch {
+	search := repoSearch{
+		ignored: func(path string) bool {
+			if appCtx.config.Ignored(path) {
+				return true
+			}
+			for _, pattern := range d.excludeOpt.Value {
+				if ignore.MatchGlob(resolveGlob(appCtx, pattern), path) {
+					return true
+				}
+			}
+			return false
+		},
+		maxDepth:       d.maxDepthOpt.Value,
+		followSymlinks: d.followSymlinksOpt.Value,
+	}
+	for _, pattern := range d.includeOpt.Value {
+		search.include = append(search.include, resolveGlob(appCtx, pattern))
+	}
+	switch {
+	case len(args) > 0:
+		for _, arg := range args {
+			search.roots = append(search.roots, resolveDir(appCtx, arg))
+		}
+	case len(appCtx.config.Roots) > 0:
+		search.roots = appCtx.config.Roots
+	default:
+		search.roots = []string{appCtx.wd}
+	}
+	return search
+}
+
+// resolveGlob resolves a glob containing a path separator against the working directory. Other
+// globs match directory names and are returned unchanged.
+func resolveGlob(appCtx appContext, pattern string) string {
+	pattern = filepath.FromSlash(pattern)
+	if filepath.Base(pattern) == pattern {
+		return pattern
+	}
+	return resolveDir(appCtx, pattern)
+}
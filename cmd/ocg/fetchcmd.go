@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ttd2089/ocg/internal/git"
+	"github.com/ttd2089/ocg/internal/opts"
+)
+
+func newFetchCmd(appCtx appContext) cmd {
+	return &fetchCmd{
+		discovery:  newDiscoveryOpts(),
+		jobsOpt:    newJobsOpt(),
+		timeoutOpt: newTimeoutOpt(),
+		appCtx:     appCtx,
+	}
+}
+
+type fetchCmd struct {
+	discovery  *discoveryOpts
+	jobsOpt    opts.IntOpt
+	timeoutOpt opts.DurationOpt
+	appCtx     appContext
+}
+
+func (f *fetchCmd) declare() cmdDecl {
+	return cmdDecl{
+		name:    "fetch",
+		summary: "Fetch all remotes of every git repository",
+		description: []string{
+			"Fetches all remotes of every repository found in <dir>, pruning remote branches that no longer exist.",
+		},
+		args: []argDecl{dirsArgDecl},
+		options: []optionDecl{
+			jobsOptDecl(&f.jobsOpt, "fetch"),
+			timeoutOptDecl(&f.timeoutOpt, "fetching each repo"),
+		},
+		optionGroups: []optionGroup{f.discovery.group()},
+	}
+}
+
+func (f *fetchCmd) run(args []string) int {
+
+	// Worktrees of the same repository share their remotes so each repository is only fetched
+	// once regardless of how many of its worktrees are found.
+	var claims repoClaims
+	progress := newProgress(os.Stderr, "fetching")
+	results, err := processRepos(
+		f.discovery.search(f.appCtx, args),
+		git.NewTimeoutCLI(f.timeoutOpt.Value),
+		f.jobsOpt.Value,
+		func(repo git.Repo) (bool, error) {
+			if !claims.claim(repo) {
+				return false, nil
+			}
+			err := repo.Fetch()
+			progress.update(repo.Name(), err)
+			return true, err
+		})
+	progress.finish()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	succeeded := 0
+	var failures []error
+	for _, result := range results {
+		if result.err != nil {
+			failures = append(failures, result.err)
+		} else if result.value {
+			succeeded++
+		}
+	}
+
+	fmt.Fprintf(os.Stdout, "fetched %d %s\n", succeeded, plural(succeeded, "repo", "repos"))
+	if len(failures) == 0 {
+		return 0
+	}
+	fmt.Fprintf(os.Stdout, "failed to fetch %d %s:\n", len(failures), plural(len(failures), "repo", "repos"))
+	for _, failure := range failures {
+		fmt.Fprintf(os.Stdout, "  %v\n", failure)
+	}
+	return 1
+}
+
+// plural returns singular if n is 1 and plural otherwise.
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"github.com/ttd2089/ocg/internal/opts"
+)
+
+// A statusFilter is an option that selects repos or branches by their status. Exactly one of repo
+// and branch is set.
+type statusFilter struct {
+	opt    opts.FlagOpt
+	help   string
+	repo   func(repoSummary) bool
+	branch func(branchSummary) bool
+}
+
+// statusFilters are the options that filter the repos and branches reported by a command.
+//
+// Repo filters select repos while branch filters select the branches within each repo and the
+// repos with at least one selected branch. By default repos and branches must match every filter
+// that is set; when the any option is set they only need to match one of them.
+type statusFilters struct {
+	anyOpt  opts.FlagOpt
+	filters []*statusFilter
+}
+
+func newStatusFilters() *statusFilters {
+	return &statusFilters{
+		anyOpt: newLongFlagOpt("any"),
+		filters: []*statusFilter{
+			{
+				opt:    newLongFlagOpt("ahead"),
+				help:   "Only list branches that are ahead of the branch they track",
+				branch: func(b branchSummary) bool { return b.Tracking != nil && b.Tracking.Ahead > 0 },
+			},
+			{
+				opt:    newLongFlagOpt("behind"),
+				help:   "Only list branches that are behind the branch they track",
+				branch: func(b branchSummary) bool { return b.Tracking != nil && b.Tracking.Behind > 0 },
+			},
+			{
+				opt:  newLongFlagOpt("clean"),
+				help: "Only list repos with no unfinished work",
+				repo: repoSummary.clean,
+			},
+			{
+				opt:  newLongFlagOpt("dirty"),
+				help: "Only list repos with uncommitted changes",
+				repo: func(r repoSummary) bool { return r.Dirty },
+			},
+			{
+				opt:  newLongFlagOpt("has-stash"),
+				help: "Only list repos that have stashed changes",
+				repo: func(r repoSummary) bool { return r.Stashes > 0 },
+			},
+			{
+				opt:    newLongFlagOpt("unmerged"),
+				help:   "Only list branches that are not merged into the default branch",
+				branch: branchSummary.unmerged,
+			},
+			{
+				opt:    newLongFlagOpt("untracked"),
+				help:   "Only list branches that do not track a remote branch",
+				branch: branchSummary.untracked,
+			},
+		},
+	}
+}
+
+func newLongFlagOpt(name string) opts.FlagOpt {
+	return opts.FlagOpt{
+		OptionName: opts.OptionName{
+			LongName: name,
+		},
+	}
+}
+
+// group returns the declarations of the options that configure the statusFilters.
+func (f *statusFilters) group() optionGroup {
+	options := []optionDecl{
+		{
+			opt:  &f.anyOpt,
+			help: "List repos and branches that match any filter instead of all filters",
+		},
+	}
+	for _, filter := range f.filters {
+		options = append(options, optionDecl{opt: &filter.opt, help: filter.help})
+	}
+	return optionGroup{
+		title:   "filters",
+		options: options,
+	}
+}
+
+// apply returns the summaries that match the filters that are set, with the branches of each
+// summary narrowed to those that match when any branch filters are set.
+func (f *statusFilters) apply(summaries []repoSummary) []repoSummary {
+	var repoFilters []func(repoSummary) bool
+	var branchFilters []func(branchSummary) bool
+	for _, filter := range f.filters {
+		if !filter.opt.Value {
+			continue
+		}
+		if filter.repo != nil {
+			repoFilters = append(repoFilters, filter.repo)
+		} else {
+			branchFilters = append(branchFilters, filter.branch)
+		}
+	}
+	if len(repoFilters) == 0 && len(branchFilters) == 0 {
+		return summaries
+	}
+
+	filtered := make([]repoSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		branches := make([]branchSummary, 0, len(summary.Branches))
+		for _, branch := range summary.Branches {
+			if matches(f.anyOpt.Value, branch, branchFilters) {
+				branches = append(branches, branch)
+			}
+		}
+		repoMatches := matches(f.anyOpt.Value, summary, repoFilters)
+		var keep bool
+		if f.anyOpt.Value {
+			keep = (len(repoFilters) > 0 && repoMatches) || len(branches) > 0
+		} else {
+			keep = repoMatches && (len(branchFilters) == 0 || len(branches) > 0)
+		}
+		if !keep {
+			continue
+		}
+		if len(branchFilters) > 0 {
+			summary.Branches = branches
+		}
+		filtered = append(filtered, summary)
+	}
+	return filtered
+}
+
+// matches returns a bool indicating whether value matches any of the filters when matchAny is true
+// or all of the filters otherwise. An empty set of filters matches every value when matchAny is
+// false and no values when matchAny is true.
+func matches[T any](matchAny bool, value T, filters []func(T) bool) bool {
+	for _, filter := range filters {
+		if filter(value) == matchAny {
+			return matchAny
+		}
+	}
+	return !matchAny
+}
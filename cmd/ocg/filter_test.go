@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStatusFilters(t *testing.T) {
+
+	summaries := []repoSummary{
+		{
+			Name:  "dirty",
+			Dirty: true,
+			Branches: []branchSummary{
+				{Name: "ahead", Tracking: &trackingSummary{Ahead: 1}},
+				{Name: "synced", Tracking: &trackingSummary{}},
+			},
+		},
+		{
+			Name:    "stashed",
+			Stashes: 1,
+			Branches: []branchSummary{
+				{Name: "behind", Tracking: &trackingSummary{Behind: 1}},
+				{Name: "local"},
+			},
+		},
+		{
+			Name: "quiet",
+			Branches: []branchSummary{
+				{Name: "synced", Tracking: &trackingSummary{}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		set      []string
+		expected []string
+	}{
+		{
+			name:     "Returns everything when no filters are set",
+			expected: []string{"dirty[ahead synced]", "stashed[behind local]", "quiet[synced]"},
+		},
+		{
+			name:     "Returns everything when only any is set",
+			set:      []string{"any"},
+			expected: []string{"dirty[ahead synced]", "stashed[behind local]", "quiet[synced]"},
+		},
+		{
+			name:     "Keeps every branch of the repos matching a repo filter",
+			set:      []string{"dirty"},
+			expected: []string{"dirty[ahead synced]"},
+		},
+		{
+			name:     "Narrows branches to those matching a branch filter",
+			set:      []string{"ahead"},
+			expected: []string{"dirty[ahead]"},
+		},
+		{
+			name:     "Requires repos to match every repo filter",
+			set:      []string{"dirty", "has-stash"},
+			expected: []string{},
+		},
+		{
+			name:     "Requires branches to match every branch filter",
+			set:      []string{"ahead", "untracked"},
+			expected: []string{},
+		},
+		{
+			name:     "Requires repos matching the repo filters to have a branch matching the branch filters",
+			set:      []string{"dirty", "behind"},
+			expected: []string{},
+		},
+		{
+			name:     "Combines repo and branch filters",
+			set:      []string{"dirty", "ahead"},
+			expected: []string{"dirty[ahead]"},
+		},
+		{
+			name:     "Matches repos that match any repo filter with any",
+			set:      []string{"any", "dirty", "has-stash"},
+			expected: []string{"dirty[ahead synced]", "stashed[behind local]"},
+		},
+		{
+			name:     "Matches branches that match any branch filter with any",
+			set:      []string{"any", "ahead", "untracked"},
+			expected: []string{"dirty[ahead]", "stashed[local]"},
+		},
+		{
+			name:     "Matches repos that match a repo filter or have a branch matching a branch filter with any",
+			set:      []string{"any", "dirty", "behind"},
+			expected: []string{"dirty[]", "stashed[behind]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			underTest := newStatusFilters()
+			for _, name := range tt.set {
+				setFilter(t, underTest, name)
+			}
+			actual := []string{}
+			for _, summary := range underTest.apply(summaries) {
+				var branches []string
+				for _, branch := range summary.Branches {
+					branches = append(branches, branch.Name)
+				}
+				actual = append(actual, fmt.Sprintf("%s[%s]", summary.Name, strings.Join(branches, " ")))
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %q; got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// setFilter sets the option of the named filter, or the any option, as though it were given on
+// the command line.
+func setFilter(t *testing.T, filters *statusFilters, name string) {
+	t.Helper()
+	if name == filters.anyOpt.LongName {
+		filters.anyOpt.Value = true
+		return
+	}
+	for _, filter := range filters.filters {
+		if filter.opt.LongName == name {
+			filter.opt.Value = true
+			return
+		}
+	}
+	t.Fatalf("unknown filter '%s'", name)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// An outputFormat is a format that repo summaries can be written in.
+type outputFormat string
+
+const (
+	formatYAML   outputFormat = "yaml"
+	formatJSON   outputFormat = "json"
+	formatNDJSON outputFormat = "ndjson"
+)
+
+var outputFormats = []outputFormat{formatYAML, formatJSON, formatNDJSON}
+
+// outputFormatNames returns the names of the supported output formats.
+func outputFormatNames() []string {
+	names := make([]string, 0, len(outputFormats))
+	for _, format := range outputFormats {
+		names = append(names, string(format))
+	}
+	return names
+}
+
+// A repoListing is the document written by ocg list in the yaml and json formats.
+type repoListing struct {
+	Repos []repoSummary `json:"repos" yaml:"repos"`
+}
+
+// writeSummaries writes summaries to w in the given format. The yaml and json formats write a
+// single document containing every summary while the ndjson format writes each summary as a JSON
+// object on its own line.
+func writeSummaries(w io.Writer, format outputFormat, summaries []repoSummary) error {
+	if summaries == nil {
+		summaries = []repoSummary{}
+	}
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(repoListing{Repos: summaries})
+	case formatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, summary := range summaries {
+			if err := enc.Encode(summary); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(repoListing{Repos: summaries}); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ttd2089/ocg/internal/opts"
+)
+
+// helpWidth is the width that help text is wrapped to.
+const helpWidth = 96
+
+// writeHelp writes the lines of help text to w.
+func writeHelp(w io.Writer, lines []string) {
+	fmt.Fprintf(w, "%s\n", strings.Join(lines, "\n"))
+}
+
+// cmdHelpText returns the help text generated from the declaration of a command.
+func cmdHelpText(decl cmdDecl) []string {
+	sections := [][]string{{usageLine(decl)}}
+	for _, paragraph := range decl.description {
+		sections = append(sections, wrap(paragraph, helpWidth))
+	}
+	if len(decl.args) > 0 {
+		rows := make([][2]string, 0, len(decl.args))
+		for _, arg := range decl.args {
+			rows = append(rows, [2]string{arg.name, arg.help})
+		}
+		sections = append(sections, titled("arguments", formatTable(rows)))
+	}
+	sections = append(sections, titled("options", formatOptions(decl.options)))
+	for _, section := range decl.sections {
+		sections = append(sections, titled(section.title, section.lines))
+	}
+	for _, group := range decl.optionGroups {
+		lines := formatOptions(group.options)
+		for _, note := range group.notes {
+			lines = append(lines, "")
+			lines = append(lines, indent(wrap(note, helpWidth-2), "  ")...)
+		}
+		sections = append(sections, titled(group.title, lines))
+	}
+	return joinSections(sections)
+}
+
+// usageLine returns the line describing how to invoke the declared command.
+func usageLine(decl cmdDecl) string {
+	usage := fmt.Sprintf("usage: ocg %s [<option>...]", decl.name)
+	for _, arg := range decl.args {
+		switch {
+		case arg.required:
+			usage += fmt.Sprintf(" <%s>", arg.name)
+		case arg.repeated:
+			usage += fmt.Sprintf(" [<%s>...]", arg.name)
+		default:
+			usage += fmt.Sprintf(" [<%s>]", arg.name)
+		}
+	}
+	return usage
+}
+
+// formatOptions returns a table of options and their help text sorted by name.
+func formatOptions(options []optionDecl) []string {
+	sorted := append([]optionDecl{}, options...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sortKey(sorted[i].opt.Name()) < sortKey(sorted[j].opt.Name())
+	})
+	rows := make([][2]string, 0, len(sorted))
+	for _, option := range sorted {
+		rows = append(rows, [2]string{optionLabel(option), option.help + bindingHelp(option)})
+	}
+	return formatTable(rows)
+}
+
+func sortKey(name opts.OptionName) string {
+	if name.LongName != "" {
+		return name.LongName
+	}
+	return string(name.ShortName)
+}
+
+// optionLabel returns the names of an option and its value placeholder as they appear in help,
+// e.g. "-j, --jobs <n>".
+func optionLabel(option optionDecl) string {
+	name := option.opt.Name()
+	var label string
+	switch {
+	case name.ShortName != 0 && name.LongName != "":
+		label = fmt.Sprintf("-%c, --%s", name.ShortName, name.LongName)
+	case name.ShortName != 0:
+		label = fmt.Sprintf("-%c", name.ShortName)
+	default:
+		label = fmt.Sprintf("    --%s", name.LongName)
+	}
+	if option.value != "" {
+		label += " " + option.value
+	}
+	return label
+}
+
+// bindingHelp returns the help text describing where a bound option's value may come from and where
+// it did come from, e.g. " [env: OCG_JOBS, config: jobs, source: default]".
+func bindingHelp(option optionDecl) string {
+	bound, ok := option.opt.(*opts.BoundOption)
+	if !ok {
+		return ""
+	}
+	var parts []string
+	if bound.Env != "" {
+		parts = append(parts, "env: "+bound.Env)
+	}
+	if bound.ConfigKey != "" {
+		parts = append(parts, "config: "+bound.ConfigKey)
+	}
+	parts = append(parts, "source: "+bound.Source.String())
+	return fmt.Sprintf(" [%s]", strings.Join(parts, ", "))
+}
+
+// formatTable returns the rows as an indented two column table with the second column wrapped to
+// fit the help width.
+func formatTable(rows [][2]string) []string {
+	width := 0
+	for _, row := range rows {
+		if len(row[0]) > width {
+			width = len(row[0])
+		}
+	}
+	var lines []string
+	for _, row := range rows {
+		wrapped := wrap(row[1], helpWidth-width-6)
+		lines = append(lines, fmt.Sprintf("  %-*s    %s", width, row[0], wrapped[0]))
+		for _, line := range wrapped[1:] {
+			lines = append(lines, strings.Repeat(" ", width+6)+line)
+		}
+	}
+	return lines
+}
+
+// titled returns the lines preceded by a title.
+func titled(title string, lines []string) []string {
+	return append([]string{title + ":"}, lines...)
+}
+
+// indent returns the lines with prefix prepended to each of them.
+func indent(lines []string, prefix string) []string {
+	indented := make([]string, 0, len(lines))
+	for _, line := range lines {
+		indented = append(indented, prefix+line)
+	}
+	return indented
+}
+
+// joinSections joins sections of help text with blank lines.
+func joinSections(sections [][]string) []string {
+	var lines []string
+	for i, section := range sections {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, section...)
+	}
+	return lines
+}
+
+// wrap splits text into lines no longer than width, breaking at spaces. Words longer than width
+// are not broken.
+func wrap(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	return append(lines, line)
+}
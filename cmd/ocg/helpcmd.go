@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func newHelpCmd(appCtx appContext) cmd {
+	return &helpCmd{
+		appCtx: appCtx,
+	}
+}
+
+type helpCmd struct {
+	appCtx appContext
+}
+
+func (h *helpCmd) declare() cmdDecl {
+	return cmdDecl{
+		name:          "help",
+		summary:       "Print help text",
+		ignoresConfig: true,
+		description: []string{
+			"Prints help text for ocg or the given command.",
+		},
+		args: []argDecl{
+			{
+				name:     "command",
+				help:     "The command to print help text for",
+				complete: completeCommands,
+			},
+		},
+	}
+}
+
+func (h *helpCmd) run(args []string) int {
+
+	if len(args) == 0 {
+		writeHelp(os.Stdout, ocgHelpText(h.appCtx))
+		return 0
+	}
+
+	command := findCmd(h.appCtx, args[0])
+	if command == nil {
+		fmt.Fprintf(os.Stderr, "ocg: unknown command '%s'\n\n", args[0])
+		writeHelp(os.Stderr, ocgHelpText(h.appCtx))
+		return 1
+	}
+
+	return runCmd(h.appCtx, command, []string{"--help"})
+}
@@ -2,30 +2,13 @@ package main
 
 import (
 	"fmt"
-	"io"
 	"os"
-	"strings"
 
+	"github.com/ttd2089/ocg/internal/config"
 	"github.com/ttd2089/ocg/internal/opts"
 	"github.com/ttd2089/shgit"
 )
 
-// go build -ldflags="-X 'main.OCGVersion=<version>'"
-var OCGVersion = "0.0.0.dev"
-
-var ocgHelpText []string = []string{
-	"usage: ocg [<option>...] <command> [<cmd-option>...] [<arg>...]",
-	"",
-	"options:",
-	"  -h, --help       Invokes the help command",
-	"  -v, --version    Invokes the version command",
-	"",
-	"commands:",
-	"  list       List git repositories and their statuses",
-	"  help       Print help text",
-	"  version    Print OCG version information",
-}
-
 type ocgOptions struct {
 	help    opts.FlagOpt
 	version opts.FlagOpt
@@ -42,10 +25,18 @@ func main() {
 	ocgOpts, args, err := parseOptions(os.Args[1:])
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %s\n\n", err)
-		help(os.Stderr)
+		writeHelp(os.Stderr, ocgHelpText(appCtx))
 		os.Exit(1)
 	}
 
+	// The completion scripts run ocg __complete to complete the word under the cursor.
+	if len(args) > 0 && args[0] == completeCmdName {
+		for _, candidate := range completeWords(appCtx, args[1:]) {
+			fmt.Println(candidate)
+		}
+		return
+	}
+
 	if ocgOpts.help.Value {
 		args = append([]string{"help"}, args...)
 	} else if ocgOpts.version.Value {
@@ -54,52 +45,73 @@ func main() {
 		args = []string{"help"}
 	}
 
-	var command cmd
-
-	switch args[0] {
-	case "list":
-		command = newListCmd(appCtx)
-	case "version":
-		version()
-		return
-	case "help":
-		help(os.Stdout)
-		return
-	default:
-		fmt.Fprintf(os.Stderr, "ocg: unknown command '%s'\n\n", os.Args[1])
-		help(os.Stderr)
+	command := findCmd(appCtx, args[0])
+	if command == nil {
+		fmt.Fprintf(os.Stderr, "ocg: unknown command '%s'\n\n", args[0])
+		writeHelp(os.Stderr, ocgHelpText(appCtx))
 		os.Exit(1)
 	}
 
-	os.Exit(command.run(os.Args[2:]))
+	os.Exit(runCmd(appCtx, command, args[1:]))
+}
+
+func newOCGOptions() ocgOptions {
+	return ocgOptions{
+		help: opts.FlagOpt{
+			OptionName: opts.OptionName{
+				LongName:  "help",
+				ShortName: 'h',
+			},
+		},
+		version: opts.FlagOpt{
+			OptionName: opts.OptionName{
+				LongName:  "version",
+				ShortName: 'v',
+			},
+		},
+	}
 }
 
 func parseOptions(args []string) (ocgOptions, []string, error) {
 
-	ocgOpts := ocgOptions{}
+	ocgOpts := newOCGOptions()
 
-	ocgOpts.help = opts.FlagOpt{
-		OptionName: opts.OptionName{
-			LongName:  "help",
-			ShortName: 'h',
-		},
+	var options []opts.Option
+	for _, option := range ocgOpts.declare() {
+		options = append(options, option.opt)
 	}
+	remaining, err := opts.Parse(args, options)
 
-	ocgOpts.version = opts.FlagOpt{
-		OptionName: opts.OptionName{
-			LongName:  "version",
-			ShortName: 'v',
+	return ocgOpts, remaining, err
+}
+
+func (o *ocgOptions) declare() []optionDecl {
+	return []optionDecl{
+		{
+			opt:  &o.help,
+			help: "Invokes the help command",
+		},
+		{
+			opt:  &o.version,
+			help: "Invokes the version command",
 		},
 	}
+}
 
-	remaining, err := opts.Parse(
-		args,
-		[]opts.Option{
-			&ocgOpts.help,
-			&ocgOpts.version,
-		})
-
-	return ocgOpts, remaining, err
+// ocgHelpText returns the help text for ocg which lists its options and the summary of each
+// command.
+func ocgHelpText(appCtx appContext) []string {
+	var commandRows [][2]string
+	for _, newCmd := range commands() {
+		decl := newCmd(appCtx).declare()
+		commandRows = append(commandRows, [2]string{decl.name, decl.summary})
+	}
+	ocgOpts := newOCGOptions()
+	return joinSections([][]string{
+		{"usage: ocg [<option>...] <command> [<cmd-option>...] [<arg>...]"},
+		titled("options", formatOptions(ocgOpts.declare())),
+		titled("commands", formatTable(commandRows)),
+	})
 }
 
 func getAppContext() (appCtx appContext, err error) {
@@ -113,13 +125,11 @@ func getAppContext() (appCtx appContext, err error) {
 
 	appCtx.gitCLI = shgit.NewCLI()
 
-	return
-}
-
-func help(w io.Writer) {
-	fmt.Fprintf(w, "%s", strings.Join(ocgHelpText, "\n"))
-}
+	configPath, configErr := config.Path()
+	if configErr == nil {
+		appCtx.config, configErr = config.Load(configPath)
+	}
+	appCtx.configErr = configErr
 
-func version() {
-	fmt.Printf("ocg version %s\n", OCGVersion)
+	return
 }
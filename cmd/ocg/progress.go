@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// A progress reports the progress of an action being applied to many repos on a single line of a
+// terminal. Nothing is reported when the output is not a terminal.
+type progress struct {
+	mu      sync.Mutex
+	out     *os.File
+	enabled bool
+	action  string
+	done    int
+	failed  int
+}
+
+func newProgress(out *os.File, action string) *progress {
+	return &progress{
+		out:     out,
+		enabled: isTerminal(out),
+		action:  action,
+	}
+}
+
+// update records that the action has completed for the named repo, failing if err is not nil.
+func (p *progress) update(name string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	if err != nil {
+		p.failed++
+	}
+	if p.enabled {
+		fmt.Fprintf(p.out, "\r\x1b[K%s: %d done, %d failed (%s)", p.action, p.done, p.failed, name)
+	}
+}
+
+// finish clears the progress line.
+func (p *progress) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.enabled {
+		fmt.Fprintf(p.out, "\r\x1b[K")
+	}
+}
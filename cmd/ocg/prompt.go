@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A prompter asks the user questions and reads their answers.
+type prompter struct {
+	out io.Writer
+	in  *bufio.Reader
+}
+
+func newPrompter(out io.Writer, in io.Reader) *prompter {
+	return &prompter{
+		out: out,
+		in:  bufio.NewReader(in),
+	}
+}
+
+// ask writes question to the output and returns the user's answer in lower case. An empty answer
+// is returned if the input has been exhausted.
+func (p *prompter) ask(question string) string {
+	fmt.Fprintf(p.out, "%s ", question)
+	answer, err := p.in.ReadString('\n')
+	if err != nil {
+		fmt.Fprintf(p.out, "\n")
+	}
+	return strings.ToLower(strings.TrimSpace(answer))
+}
+
+// confirm asks a yes or no question and returns true if the user answers yes.
+func (p *prompter) confirm(question string) bool {
+	answer := p.ask(question + " [y/N]")
+	return answer == "y" || answer == "yes"
+}
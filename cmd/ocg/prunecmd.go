@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ttd2089/ocg/internal/git"
+	"github.com/ttd2089/ocg/internal/opts"
+)
+
+func newPruneCmd(appCtx appContext) cmd {
+	return &pruneCmd{
+		branchOpt: newBranchOpt(),
+		discovery: newDiscoveryOpts(),
+		jobsOpt:   newJobsOpt(),
+		yesOpt: opts.FlagOpt{
+			OptionName: opts.OptionName{
+				LongName:  "yes",
+				ShortName: 'y',
+			},
+		},
+		appCtx: appCtx,
+	}
+}
+
+type pruneCmd struct {
+	branchOpt opts.StringSliceOpt
+	discovery *discoveryOpts
+	jobsOpt   opts.IntOpt
+	yesOpt    opts.FlagOpt
+	appCtx    appContext
+}
+
+// A pruneCandidate is a branch that is eligible to be pruned.
+type pruneCandidate struct {
+	branch string
+	reason string
+
+	// keep is the reason a branch that would otherwise be pruned must be kept, if it must.
+	keep string
+}
+
+func (p *pruneCmd) declare() cmdDecl {
+	return cmdDecl{
+		name:    "prune",
+		summary: "Delete branches that are gone or merged into the default branch",
+		description: []string{
+			"Deletes local branches whose tracked remote branch no longer exists or that have been merged into the default branch of their remote in every repository found in <dir>.",
+			"Branches that are checked out, branches that track the default branch, and branches with commits that are neither merged nor pushed are never deleted. The branches to be deleted are printed and confirmation is requested before they are deleted.",
+		},
+		args: []argDecl{dirsArgDecl},
+		options: []optionDecl{
+			branchOptDecl(&p.branchOpt, "delete"),
+			jobsOptDecl(&p.jobsOpt, "inspect"),
+			{
+				opt:  &p.yesOpt,
+				help: "Delete the branches without asking for confirmation",
+			},
+		},
+		optionGroups: []optionGroup{p.discovery.group()},
+	}
+}
+
+func (p *pruneCmd) run(args []string) int {
+
+	// Branches belong to the repository rather than a worktree so each repository is only
+	// inspected once regardless of how many of its worktrees are found.
+	var claims repoClaims
+	results, err := processRepos(
+		p.discovery.search(p.appCtx, args),
+		p.appCtx.gitCLI,
+		p.jobsOpt.Value,
+		func(repo git.Repo) ([]pruneCandidate, error) {
+			if !claims.claim(repo) {
+				return nil, nil
+			}
+			return findPrunable(repo, p.branchOpt.Value)
+		})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	status := 0
+	branches, repos := 0, 0
+	for _, result := range results {
+		if result.err != nil {
+			fmt.Fprintf(os.Stdout, "%s\n  error: %v\n", result.repo.Path(), result.err)
+			status = 1
+			continue
+		}
+		if len(result.value) == 0 {
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "%s\n", result.repo.Path())
+		pruned := 0
+		for _, candidate := range result.value {
+			if candidate.keep != "" {
+				fmt.Fprintf(os.Stdout, "  keeping %s (%s): %s\n", candidate.branch, candidate.reason, candidate.keep)
+				continue
+			}
+			fmt.Fprintf(os.Stdout, "  %s (%s)\n", candidate.branch, candidate.reason)
+			pruned++
+		}
+		if pruned > 0 {
+			branches += pruned
+			repos++
+		}
+	}
+
+	if branches == 0 {
+		fmt.Fprintf(os.Stdout, "no branches to prune\n")
+		return status
+	}
+
+	question := fmt.Sprintf(
+		"delete %d %s in %d %s?",
+		branches,
+		plural(branches, "branch", "branches"),
+		repos,
+		plural(repos, "repo", "repos"))
+	if !p.yesOpt.Value && !newPrompter(os.Stdout, os.Stdin).confirm(question) {
+		return status
+	}
+
+	deleted := 0
+	for _, result := range results {
+		for _, candidate := range result.value {
+			if candidate.keep != "" {
+				continue
+			}
+			if err := result.repo.DeleteBranch(candidate.branch); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+				status = 1
+				continue
+			}
+			deleted++
+		}
+	}
+	fmt.Fprintf(os.Stdout, "deleted %d %s\n", deleted, plural(deleted, "branch", "branches"))
+	return status
+}
+
+// findPrunable returns the branches in repo that match the branch patterns and are gone or merged
+// along with the reason they must be kept if they are not safe to delete.
+func findPrunable(repo git.Repo, patterns []string) ([]pruneCandidate, error) {
+	branches, err := repo.LocalBranches()
+	if err != nil {
+		return nil, err
+	}
+	defaults, err := repo.DefaultBranches()
+	if err != nil {
+		return nil, err
+	}
+	checkedOut, err := checkedOutAnywhere(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []pruneCandidate
+	for _, branch := range branches {
+		if checkedOut[branch.Name] || !matchBranch(patterns, branch.Name) {
+			continue
+		}
+		target, hasTarget := mergeTarget(branch, defaults)
+		if hasTarget && isDefaultBranch(branch, target) {
+			continue
+		}
+		state := git.Unmerged
+		if hasTarget {
+			state, err = repo.MergeState(branch.Name, target.Name)
+			if err != nil {
+				return nil, err
+			}
+		}
+		var reasons []string
+		if branch.Gone {
+			reasons = append(reasons, "gone")
+		}
+		if state != git.Unmerged {
+			reasons = append(reasons, fmt.Sprintf("%s into %s", state, target.Name))
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+		candidate := pruneCandidate{
+			branch: branch.Name,
+			reason: strings.Join(reasons, ", "),
+		}
+		if state == git.Unmerged {
+			unpushed, err := repo.UnpushedCommits(branch.Name)
+			if err != nil {
+				return nil, err
+			}
+			if unpushed > 0 {
+				candidate.keep = fmt.Sprintf("%d unpushed %s", unpushed, plural(unpushed, "commit", "commits"))
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// checkedOutAnywhere returns the set of branches checked out in any of the worktrees of repo.
+func checkedOutAnywhere(repo git.Repo) (map[string]bool, error) {
+	worktrees, err := repo.Worktrees()
+	if err != nil {
+		return nil, err
+	}
+	checkedOut := map[string]bool{}
+	for _, worktree := range worktrees {
+		if worktree.Branch != "" {
+			checkedOut[worktree.Branch] = true
+		}
+	}
+	return checkedOut, nil
+}
+
+// isDefaultBranch returns a bool indicating whether branch is the local copy of the default branch
+// target.
+func isDefaultBranch(branch git.LocalBranch, target git.Branch) bool {
+	if branch.Tracking != nil && branch.Tracking.Name == target.Name {
+		return true
+	}
+	return strings.HasSuffix(target.Name, "/"+branch.Name)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindPrunable(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		setup    func(g gitFunc)
+		patterns []string
+		expected []string
+	}{
+		{
+			name: "Keeps gone branches with unpushed commits",
+			setup: func(g gitFunc) {
+				g("checkout", "-q", "-b", "topic")
+				commitFile(g, "a.txt", "a\n")
+				g("push", "-q", "-u", "origin", "topic")
+				commitFile(g, "a.txt", "a\nb\n")
+				g("push", "-q", "origin", "--delete", "topic")
+				g("checkout", "-q", "main")
+			},
+			expected: []string{"topic (gone): 2 unpushed commits"},
+		},
+		{
+			name: "Prunes gone branches whose commits are all on a remote",
+			setup: func(g gitFunc) {
+				g("checkout", "-q", "-b", "topic")
+				commitFile(g, "a.txt", "a\n")
+				g("push", "-q", "-u", "origin", "topic")
+				g("push", "-q", "origin", "topic:archive/topic")
+				g("push", "-q", "origin", "--delete", "topic")
+				g("checkout", "-q", "main")
+			},
+			expected: []string{"topic (gone)"},
+		},
+		{
+			name: "Prunes squash merged branches",
+			setup: func(g gitFunc) {
+				g("checkout", "-q", "-b", "topic")
+				commitFile(g, "a.txt", "a\n")
+				commitFile(g, "a.txt", "a\nb\n")
+				g("checkout", "-q", "main")
+				g("merge", "-q", "--squash", "topic")
+				g("commit", "-q", "-m", "squashed")
+				g("push", "-q", "origin", "main")
+			},
+			expected: []string{"topic (squash-merged into origin/main)"},
+		},
+		{
+			name: "Ignores unmerged branches that aren't gone",
+			setup: func(g gitFunc) {
+				g("checkout", "-q", "-b", "topic")
+				commitFile(g, "a.txt", "a\n")
+				g("checkout", "-q", "main")
+			},
+			expected: []string{},
+		},
+		{
+			name: "Skips branches checked out in other worktrees",
+			setup: func(g gitFunc) {
+				g("branch", "merged")
+				g("branch", "elsewhere")
+				path := filepath.Join(filepath.Dir(g("rev-parse", "--show-toplevel")), "worktree")
+				g("worktree", "add", "-q", path, "elsewhere")
+			},
+			expected: []string{"merged (merged into origin/main)"},
+		},
+		{
+			name: "Skips the local default branch when it isn't checked out",
+			setup: func(g gitFunc) {
+				g("checkout", "-q", "--detach")
+			},
+			expected: []string{},
+		},
+		{
+			name: "Only considers branches matching the branch patterns",
+			setup: func(g gitFunc) {
+				g("branch", "feature/a")
+				g("branch", "feature/b")
+				g("branch", "fix/c")
+			},
+			patterns: []string{"feature/*"},
+			expected: []string{"feature/a (merged into origin/main)", "feature/b (merged into origin/main)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, g := newTestRepo(t)
+			tt.setup(g)
+
+			candidates, err := findPrunable(openTestRepo(t, path), tt.patterns)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual := []string{}
+			for _, candidate := range candidates {
+				description := fmt.Sprintf("%s (%s)", candidate.branch, candidate.reason)
+				if candidate.keep != "" {
+					description += ": " + candidate.keep
+				}
+				actual = append(actual, description)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %q; got %q", tt.expected, actual)
+			}
+		})
+	}
+}
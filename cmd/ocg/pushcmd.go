@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ttd2089/ocg/internal/git"
+	"github.com/ttd2089/ocg/internal/opts"
+)
+
+// defaultProtectedBranches are the names of remote branches that ocg push won't push to unless
+// explicitly allowed.
+var defaultProtectedBranches = []string{"main", "master"}
+
+func newPushCmd(appCtx appContext) cmd {
+	return &pushCmd{
+		allowProtectedOpt: opts.FlagOpt{
+			OptionName: opts.OptionName{
+				LongName: "allow-protected",
+			},
+		},
+		branchOpt: newBranchOpt(),
+		discovery: newDiscoveryOpts(),
+		dryRunOpt: opts.FlagOpt{
+			OptionName: opts.OptionName{
+				LongName:  "dry-run",
+				ShortName: 'n',
+			},
+		},
+		interactiveOpt: opts.FlagOpt{
+			OptionName: opts.OptionName{
+				LongName:  "interactive",
+				ShortName: 'i',
+			},
+		},
+		jobsOpt:    newJobsOpt(),
+		timeoutOpt: newTimeoutOpt(),
+		appCtx:     appCtx,
+	}
+}
+
+type pushCmd struct {
+	allowProtectedOpt opts.FlagOpt
+	branchOpt         opts.StringSliceOpt
+	discovery         *discoveryOpts
+	dryRunOpt         opts.FlagOpt
+	interactiveOpt    opts.FlagOpt
+	jobsOpt           opts.IntOpt
+	timeoutOpt        opts.DurationOpt
+	appCtx            appContext
+}
+
+// A pushCandidate is a branch that is strictly ahead of the branch it tracks.
+type pushCandidate struct {
+	branch       string
+	remote       string
+	remoteBranch string
+	ahead        int
+
+	// refused is the reason the branch must not be pushed, if it mustn't.
+	refused string
+}
+
+func (c pushCandidate) String() string {
+	return fmt.Sprintf(
+		"%s -> %s/%s (%d %s)",
+		c.branch,
+		c.remote,
+		c.remoteBranch,
+		c.ahead,
+		plural(c.ahead, "commit", "commits"))
+}
+
+func (p *pushCmd) declare() cmdDecl {
+	return cmdDecl{
+		name:    "push",
+		summary: "Push branches that are ahead of the branches they track",
+		description: []string{
+			"Pushes every local branch that is strictly ahead of the branch it tracks in every repository found in <dir>. Branches that track protected branches are not pushed unless --allow-protected is specified. The protected branches are main and master unless the config file specifies others.",
+		},
+		args: []argDecl{dirsArgDecl},
+		options: []optionDecl{
+			{
+				opt:  &p.allowProtectedOpt,
+				help: "Push branches that track protected branches",
+			},
+			branchOptDecl(&p.branchOpt, "push"),
+			{
+				opt:  &p.dryRunOpt,
+				help: "Print the branches that would be pushed without pushing them",
+			},
+			{
+				opt:  &p.interactiveOpt,
+				help: "Ask before pushing each branch",
+			},
+			jobsOptDecl(&p.jobsOpt, "inspect"),
+			timeoutOptDecl(&p.timeoutOpt, "pushing each branch"),
+		},
+		optionGroups: []optionGroup{p.discovery.group()},
+	}
+}
+
+func (p *pushCmd) run(args []string) int {
+
+	// Branches belong to the repository rather than a worktree so each repository is only
+	// inspected once regardless of how many of its worktrees are found.
+	var claims repoClaims
+	results, err := processRepos(
+		p.discovery.search(p.appCtx, args),
+		git.NewTimeoutCLI(p.timeoutOpt.Value),
+		p.jobsOpt.Value,
+		func(repo git.Repo) ([]pushCandidate, error) {
+			if !claims.claim(repo) {
+				return nil, nil
+			}
+			return p.findPushable(repo)
+		})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	status := 0
+	prompter := newPrompter(os.Stdout, os.Stdin)
+	for _, result := range results {
+		if result.err != nil {
+			fmt.Fprintf(os.Stdout, "%s\n  error: %v\n", result.repo.Path(), result.err)
+			status = 1
+			continue
+		}
+		if len(result.value) == 0 {
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "%s\n", result.repo.Path())
+		for _, candidate := range result.value {
+			switch {
+			case candidate.refused != "":
+				fmt.Fprintf(os.Stdout, "  refused %s: %s\n", candidate, candidate.refused)
+			case p.dryRunOpt.Value:
+				fmt.Fprintf(os.Stdout, "  would push %s\n", candidate)
+			case p.interactiveOpt.Value && !prompter.confirm(fmt.Sprintf("  push %s?", candidate)):
+				fmt.Fprintf(os.Stdout, "  skipped %s\n", candidate)
+			default:
+				err := result.repo.Push(candidate.remote, candidate.branch, candidate.remoteBranch)
+				if err != nil {
+					fmt.Fprintf(os.Stdout, "  failed %s: %v\n", candidate, err)
+					status = 1
+					continue
+				}
+				fmt.Fprintf(os.Stdout, "  pushed %s\n", candidate)
+			}
+		}
+	}
+	return status
+}
+
+// findPushable returns the branches in repo that are strictly ahead of the branches they track.
+func (p *pushCmd) findPushable(repo git.Repo) ([]pushCandidate, error) {
+	branches, err := repo.LocalBranches()
+	if err != nil {
+		return nil, err
+	}
+	var candidates []pushCandidate
+	for _, branch := range branches {
+		if branch.Tracking == nil || branch.Ahead == 0 || branch.Behind > 0 {
+			continue
+		}
+		if !matchBranch(p.branchOpt.Value, branch.Name) {
+			continue
+		}
+		candidate := pushCandidate{
+			branch:       branch.Name,
+			remote:       branch.Remote,
+			remoteBranch: branch.RemoteBranch,
+			ahead:        branch.Ahead,
+		}
+		if p.isProtected(repo, candidate.remoteBranch) {
+			candidate.refused = fmt.Sprintf(
+				"%s is protected (use --allow-protected to push it)",
+				candidate.remoteBranch)
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// isProtected returns a bool indicating whether pushing to the named remote branch from repo is
+// refused.
+func (p *pushCmd) isProtected(repo git.Repo, remoteBranch string) bool {
+	if p.allowProtectedOpt.Value {
+		return false
+	}
+	protected := p.appCtx.config.Protected(repo.Path())
+	if protected == nil {
+		protected = defaultProtectedBranches
+	}
+	for _, name := range protected {
+		if remoteBranch == name {
+			return true
+		}
+	}
+	return false
+}
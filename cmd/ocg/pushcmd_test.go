@@ -0,0 +1,137 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ttd2089/ocg/internal/config"
+)
+
+func TestFindPushable(t *testing.T) {
+
+	// Each test repo has main, master and topic branches that are one commit ahead of the
+	// branches they track.
+	setup := func(g gitFunc) {
+		makeAhead(g, "master")
+		makeAhead(g, "topic")
+		commitFile(g, "main.txt", "main\n")
+	}
+
+	tests := []struct {
+		name           string
+		setup          func(g gitFunc)
+		config         func(path string) config.Config
+		allowProtected bool
+		expected       []string
+	}{
+		{
+			name:  "Refuses to push to main and master by default",
+			setup: setup,
+			expected: []string{
+				"main -> origin/main (1 commit): main is protected (use --allow-protected to push it)",
+				"master -> origin/master (1 commit): master is protected (use --allow-protected to push it)",
+				"topic -> origin/topic (1 commit)",
+			},
+		},
+		{
+			name:  "Uses the protected branches from the config instead of the defaults",
+			setup: setup,
+			config: func(path string) config.Config {
+				return config.Config{ProtectedBranches: []string{"topic"}}
+			},
+			expected: []string{
+				"main -> origin/main (1 commit)",
+				"master -> origin/master (1 commit)",
+				"topic -> origin/topic (1 commit): topic is protected (use --allow-protected to push it)",
+			},
+		},
+		{
+			name:  "Uses the protected branches for the repo instead of the global ones",
+			setup: setup,
+			config: func(path string) config.Config {
+				return config.Config{
+					ProtectedBranches: []string{"topic"},
+					Repos: []config.RepoConfig{
+						{Path: path, ProtectedBranches: []string{"master"}},
+					},
+				}
+			},
+			expected: []string{
+				"main -> origin/main (1 commit)",
+				"master -> origin/master (1 commit): master is protected (use --allow-protected to push it)",
+				"topic -> origin/topic (1 commit)",
+			},
+		},
+		{
+			name:           "Pushes protected branches when allowed",
+			setup:          setup,
+			allowProtected: true,
+			expected: []string{
+				"main -> origin/main (1 commit)",
+				"master -> origin/master (1 commit)",
+				"topic -> origin/topic (1 commit)",
+			},
+		},
+		{
+			name: "Ignores branches that are behind or diverged",
+			setup: func(g gitFunc) {
+				makeAhead(g, "behind")
+				g("push", "-q", "origin", "behind")
+				g("branch", "-q", "-f", "behind", "main")
+				makeAhead(g, "diverged")
+				g("push", "-q", "origin", "diverged")
+				g("checkout", "-q", "diverged")
+				g("reset", "-q", "--hard", "main")
+				commitFile(g, "other.txt", "other\n")
+				g("checkout", "-q", "main")
+			},
+			expected: []string{},
+		},
+		{
+			name: "Ignores branches that don't track a remote branch",
+			setup: func(g gitFunc) {
+				g("checkout", "-q", "-b", "local")
+				commitFile(g, "local.txt", "local\n")
+				g("checkout", "-q", "main")
+			},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, g := newTestRepo(t)
+			tt.setup(g)
+
+			appCtx := appContext{}
+			if tt.config != nil {
+				appCtx.config = tt.config(path)
+			}
+			underTest := newPushCmd(appCtx).(*pushCmd)
+			underTest.allowProtectedOpt.Value = tt.allowProtected
+			candidates, err := underTest.findPushable(openTestRepo(t, path))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual := []string{}
+			for _, candidate := range candidates {
+				description := candidate.String()
+				if candidate.refused != "" {
+					description += ": " + candidate.refused
+				}
+				actual = append(actual, description)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %q; got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// makeAhead creates the named branch tracking a remote branch that it's one commit ahead of.
+func makeAhead(g gitFunc, name string) {
+	g("checkout", "-q", "-b", name)
+	g("push", "-q", "-u", "origin", name)
+	commitFile(g, name+".txt", name+"\n")
+	g("checkout", "-q", "main")
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ttd2089/ocg/internal/git"
+	"github.com/ttd2089/ocg/internal/ignore"
+	"github.com/ttd2089/ocg/internal/opts"
+	"github.com/ttd2089/shgit"
+)
+
+// A repoSearch describes where to look for repos.
+type repoSearch struct {
+
+	// roots are the directories whose trees are searched.
+	roots []string
+
+	// ignored returns true for directories that must not be searched.
+	ignored func(path string) bool
+
+	// maxDepth is the maximum depth below each root to search, or 0 for no limit.
+	maxDepth int
+
+	// include holds globs that repos must match one of to be included, unless it's empty.
+	include []string
+
+	// followSymlinks indicates whether symlinks to directories are searched.
+	followSymlinks bool
+}
+
+// A dirID identifies a directory regardless of the path used to reach it.
+type dirID struct {
+	dev  uint64
+	ino  uint64
+	path string
+}
+
+// dirIDFromPath returns a dirID that identifies the directory at path by its path with symlinks
+// resolved.
+func dirIDFromPath(path string) dirID {
+	if realPath, err := filepath.EvalSymlinks(path); err == nil {
+		path = realPath
+	}
+	return dirID{path: path}
+}
+
+// resolveDir returns dir resolved against the working directory.
+func resolveDir(appCtx appContext, dir string) string {
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(appCtx.wd, dir)
+}
+
+// samePath returns a bool indicating whether a and b refer to the same path once symlinks are
+// resolved.
+func samePath(a, b string) bool {
+	if a == b {
+		return true
+	}
+	realA, errA := filepath.EvalSymlinks(a)
+	realB, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && realA == realB
+}
+
+// newJobsOpt returns the option used to limit the number of repos processed concurrently.
+func newJobsOpt() opts.IntOpt {
+	return opts.IntOpt{
+		OptionName: opts.OptionName{
+			LongName:  "jobs",
+			ShortName: 'j',
+		},
+		Value: runtime.NumCPU(),
+	}
+}
+
+// validateJobs returns an error if jobs is not a valid value for the jobs option.
+func validateJobs(jobs int) error {
+	if jobs < 1 {
+		return opts.NewInvalidOptionValueHelpText("jobs", fmt.Sprint(jobs), "must be at least 1")
+	}
+	return nil
+}
+
+// jobsOptDecl declares opt as the option used to limit the number of repos processed concurrently.
+// The help text describes the processing with verb, e.g. "inspect".
+func jobsOptDecl(opt *opts.IntOpt, verb string) optionDecl {
+	return optionDecl{
+		opt:      opts.Bind(opt, "OCG_JOBS", "jobs"),
+		value:    "<n>",
+		help:     fmt.Sprintf("The number of repos to %s concurrently (defaults to the number of CPUs)", verb),
+		validate: func() error { return validateJobs(opt.Value) },
+	}
+}
+
+// newTimeoutOpt returns the option used to limit how long a git command that talks to a remote
+// can run for.
+func newTimeoutOpt() opts.DurationOpt {
+	return opts.DurationOpt{
+		OptionName: opts.OptionName{
+			LongName:  "timeout",
+			ShortName: 't',
+		},
+		Value: 2 * time.Minute,
+	}
+}
+
+// validateTimeout returns an error if timeout is not a valid value for the timeout option.
+func validateTimeout(timeout time.Duration) error {
+	if timeout < 0 {
+		return opts.NewInvalidOptionValueHelpText("timeout", timeout.String(), "must not be negative")
+	}
+	return nil
+}
+
+// timeoutOptDecl declares opt as the option used to limit how long a git command that talks to a
+// remote can run for. The help text describes what is limited with activity, e.g. "fetching each
+// repo".
+func timeoutOptDecl(opt *opts.DurationOpt, activity string) optionDecl {
+	return optionDecl{
+		opt:      opts.Bind(opt, "OCG_TIMEOUT", "timeout"),
+		value:    "<duration>",
+		help:     fmt.Sprintf("The maximum time to spend %s, e.g. 30s or 2m (defaults to 2m; 0 means no limit)", activity),
+		validate: func() error { return validateTimeout(opt.Value) },
+	}
+}
+
+// newBranchOpt returns the option used to restrict a command to the branches matching its globs.
+func newBranchOpt() opts.StringSliceOpt {
+	return opts.StringSliceOpt{
+		OptionName: opts.OptionName{
+			LongName:  "branch",
+			ShortName: 'b',
+		},
+	}
+}
+
+// branchOptDecl declares opt as the option used to restrict a command to the branches matching its
+// globs. The help text describes what is done to the branches with verb, e.g. "push".
+func branchOptDecl(opt *opts.StringSliceOpt, verb string) optionDecl {
+	return optionDecl{
+		opt:      opt,
+		value:    "<glob>",
+		help:     fmt.Sprintf("Only %s branches whose names match glob; may be repeated", verb),
+		validate: func() error { return validateGlobs(*opt) },
+		complete: completeBranches,
+	}
+}
+
+// matchBranch returns a bool indicating whether the named branch matches any of patterns, which
+// are the values of a branch option. Every branch matches when there are no patterns.
+func matchBranch(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// dirsArgDecl declares the <dir>... argument of the commands that search for repos.
+var dirsArgDecl = argDecl{
+	name:     "dir",
+	help:     "A directory to search for repositories; repeat to search several (defaults to the roots in the config file, or the current directory)",
+	repeated: true,
+	complete: completeRepos,
+}
+
+// walkRepos calls fn for each git repository in the trees rooted at the search roots along with
+// the root it was found under. Repositories nested within other repositories are only found when
+// they're checked out submodules of the repositories containing them. Directories that the search
+// or .ocgignore files ignore, and directories deeper than the search's max depth are not searched.
+// Symlinks to directories are only searched when the search follows symlinks, in which case each
+// directory is only searched once however many paths lead to it, which also prevents symlink
+// cycles from being searched forever. Repositories reachable from more than one root, or via
+// symlinks, are only included for the first root they're found under.
+func walkRepos(search repoSearch, gitCLI shgit.CLI, fn func(root string, repo git.Repo) error) error {
+	seen := map[string]bool{}
+	visited := map[dirID]bool{}
+	for _, root := range search.roots {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return err
+		}
+		ignoreFiles := ignore.NewMatcher(absRoot)
+		var walk fs.WalkDirFunc
+		walk = func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.Type()&fs.ModeSymlink != 0 {
+				if !search.followSymlinks {
+					return nil
+				}
+				info, err := os.Stat(path)
+				if err != nil || !info.IsDir() {
+					return nil
+				}
+				return filepath.WalkDir(withTrailingSeparator(path), walk)
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			path = filepath.Clean(path)
+			if path != absRoot {
+				if search.ignored != nil && search.ignored(path) {
+					return filepath.SkipDir
+				}
+				ignored, err := ignoreFiles.Ignored(path, true)
+				if err != nil {
+					return err
+				}
+				if ignored {
+					return filepath.SkipDir
+				}
+			}
+			if search.followSymlinks {
+				info, err := os.Stat(path)
+				if err != nil {
+					return err
+				}
+				id := newDirID(path, info)
+				if visited[id] {
+					return filepath.SkipDir
+				}
+				visited[id] = true
+			}
+			repo, err := git.NewRepo(path, gitCLI)
+			if errors.Is(err, git.ErrNotAGitRepo) {
+				if search.maxDepth > 0 && depth(absRoot, path) >= search.maxDepth {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if !search.includes(path) {
+				return filepath.SkipDir
+			}
+			realPath, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				return err
+			}
+			if seen[realPath] {
+				return filepath.SkipDir
+			}
+			seen[realPath] = true
+			if err := fn(absRoot, repo); err != nil {
+				return err
+			}
+
+			// The rest of the repo's tree isn't searched, but its submodules are repos in their
+			// own right.
+			submodules, err := repo.Submodules()
+			if err != nil {
+				return err
+			}
+			for _, submodule := range submodules {
+				if _, err := os.Stat(submodule); err != nil {
+					continue
+				}
+				if err := filepath.WalkDir(submodule, walk); err != nil {
+					return err
+				}
+			}
+			return filepath.SkipDir
+		}
+		// The root is followed even when it's a symlink.
+		if err := filepath.WalkDir(withTrailingSeparator(absRoot), walk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withTrailingSeparator returns path with a trailing separator, which makes WalkDir follow path
+// when it's a symlink to a directory.
+func withTrailingSeparator(path string) string {
+	if strings.HasSuffix(path, string(filepath.Separator)) {
+		return path
+	}
+	return path + string(filepath.Separator)
+}
+
+// includes returns a bool indicating whether the repo at path matches the search's include globs.
+func (s repoSearch) includes(path string) bool {
+	if len(s.include) == 0 {
+		return true
+	}
+	for _, pattern := range s.include {
+		if ignore.MatchGlob(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// depth returns the number of directories between root and its descendant path.
+func depth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// A repoClaims tracks which repositories have been claimed by a worker so that work that applies to
+// a whole repository, rather than one of its worktrees, is only done once.
+type repoClaims struct {
+	claimed sync.Map
+}
+
+// claim returns true the first time it's called for any of the worktrees of a repository.
+func (c *repoClaims) claim(repo git.Repo) bool {
+	_, loaded := c.claimed.LoadOrStore(repo.CommonDir(), true)
+	return !loaded
+}
+
+// A repoResult is the outcome of processing a single repository.
+type repoResult[T any] struct {
+	root  string
+	repo  git.Repo
+	value T
+	err   error
+}
+
+// processRepos discovers the repositories described by search and calls process for each of
+// them using up to jobs concurrent workers. Repositories are processed while discovery is still in
+// progress. The results are sorted by repository path.
+func processRepos[T any](
+	search repoSearch,
+	gitCLI shgit.CLI,
+	jobs int,
+	process func(git.Repo) (T, error),
+) ([]repoResult[T], error) {
+
+	type foundRepo struct {
+		root string
+		repo git.Repo
+	}
+
+	repos := make(chan foundRepo)
+	results := make(chan repoResult[T])
+
+	var workers sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for found := range repos {
+				value, err := process(found.repo)
+				results <- repoResult[T]{root: found.root, repo: found.repo, value: value, err: err}
+			}
+		}()
+	}
+
+	var walkErr error
+	go func() {
+		walkErr = walkRepos(search, gitCLI, func(root string, repo git.Repo) error {
+			repos <- foundRepo{root: root, repo: repo}
+			return nil
+		})
+		close(repos)
+		workers.Wait()
+		close(results)
+	}()
+
+	var collected []repoResult[T]
+	for result := range results {
+		collected = append(collected, result)
+	}
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	sort.Slice(collected, func(i, j int) bool {
+		return collected[i].repo.Path() < collected[j].repo.Path()
+	})
+	return collected, nil
+}
+
+// summarizeRepos returns summaries of the repositories described by search, processing up to jobs
+// repositories concurrently. The summaries are sorted by repository path. Branches and stashes
+// belong to a repository rather than one of its worktrees so they're only included in the summary
+// of the first of its worktrees.
+func summarizeRepos(
+	search repoSearch,
+	gitCLI shgit.CLI,
+	jobs int,
+	options summaryOptions,
+) ([]repoSummary, error) {
+	results, err := processRepos(search, gitCLI, jobs, func(repo git.Repo) (repoSummary, error) {
+		return summarizeRepo(repo, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The worktrees are claimed in path order rather than as they're processed so the summaries
+	// don't depend on which worker finishes first.
+	var claims repoClaims
+	summaries := make([]repoSummary, 0, len(results))
+	for _, result := range results {
+		if result.err != nil {
+			return nil, result.err
+		}
+		result.value.Root = result.root
+		if !claims.claim(result.repo) {
+			result.value.Stashes = 0
+			result.value.Branches = []branchSummary{}
+		}
+		summaries = append(summaries, result.value)
+	}
+	return summaries, nil
+}
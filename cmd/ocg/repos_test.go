@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestMatchBranch(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		patterns []string
+		branch   string
+		expected bool
+	}{
+		{
+			name:     "Matches every branch without patterns",
+			branch:   "main",
+			expected: true,
+		},
+		{
+			name:     "Matches exact names",
+			patterns: []string{"main"},
+			branch:   "main",
+			expected: true,
+		},
+		{
+			name:     "Doesn't match other names",
+			patterns: []string{"main"},
+			branch:   "mainline",
+			expected: false,
+		},
+		{
+			name:     "Matches globs",
+			patterns: []string{"feature/*"},
+			branch:   "feature/login",
+			expected: true,
+		},
+		{
+			name:     "Doesn't match across slashes with *",
+			patterns: []string{"feature/*"},
+			branch:   "feature/login/fix",
+			expected: false,
+		},
+		{
+			name:     "Matches any of several patterns",
+			patterns: []string{"fix/*", "feature/*"},
+			branch:   "feature/login",
+			expected: true,
+		},
+		{
+			name:     "Doesn't match invalid patterns",
+			patterns: []string{"["},
+			branch:   "[",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := matchBranch(tt.patterns, tt.branch); actual != tt.expected {
+				t.Errorf("expected %v; got %v", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ttd2089/ocg/internal/git"
+	"github.com/ttd2089/ocg/internal/opts"
+)
+
+func newStatusCmd(appCtx appContext) cmd {
+	return &statusCmd{
+		discovery: newDiscoveryOpts(),
+		jobsOpt:   newJobsOpt(),
+		appCtx:    appCtx,
+	}
+}
+
+type statusCmd struct {
+	discovery *discoveryOpts
+	jobsOpt   opts.IntOpt
+	appCtx    appContext
+}
+
+// A statusSymbol is a single column of the status view.
+type statusSymbol struct {
+	symbol string
+	color  color
+	help   string
+	test   func(repoSummary) bool
+}
+
+var statusSymbols = []statusSymbol{
+	{
+		symbol: "*",
+		color:  colorRed,
+		help:   "The working tree has uncommitted changes",
+		test:   func(r repoSummary) bool { return r.Dirty },
+	},
+	{
+		symbol: "$",
+		color:  colorRed,
+		help:   "The repository has stashed changes",
+		test:   func(r repoSummary) bool { return r.Stashes > 0 },
+	},
+	{
+		symbol: "@",
+		color:  colorRed,
+		help:   "HEAD is detached at a commit that is not on any branch",
+		test:   func(r repoSummary) bool { return r.Head.lost() },
+	},
+	{
+		symbol: "?",
+		color:  colorBlue,
+		help:   "A branch does not track a remote branch",
+		test:   anyBranch(branchSummary.untracked),
+	},
+	{
+		symbol: "↑",
+		color:  colorYellow,
+		help:   "A branch is ahead of the branch it tracks",
+		test:   anyBranch(branchSummary.ahead),
+	},
+	{
+		symbol: "↓",
+		color:  colorCyan,
+		help:   "A branch is behind the branch it tracks",
+		test:   anyBranch(branchSummary.behind),
+	},
+	{
+		symbol: "⇅",
+		color:  colorMagenta,
+		help:   "A branch has diverged from the branch it tracks",
+		test:   anyBranch(branchSummary.diverged),
+	},
+	{
+		symbol: "✗",
+		color:  colorRed,
+		help:   "A branch is not merged into the default branch of its remote",
+		test:   anyBranch(branchSummary.unmerged),
+	},
+}
+
+// operationLabels are the labels used to flag repositories with operations in progress.
+var operationLabels = map[git.Operation]string{
+	git.OperationRebase:     "REBASING",
+	git.OperationAm:         "AM",
+	git.OperationMerge:      "MERGING",
+	git.OperationCherryPick: "CHERRY-PICKING",
+	git.OperationRevert:     "REVERTING",
+	git.OperationBisect:     "BISECTING",
+}
+
+func anyBranch(test func(branchSummary) bool) func(repoSummary) bool {
+	return func(r repoSummary) bool {
+		for _, b := range r.Branches {
+			if test(b) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (s *statusCmd) declare() cmdDecl {
+	symbols := make([]string, 0, len(statusSymbols))
+	for _, sym := range statusSymbols {
+		symbols = append(symbols, fmt.Sprintf("  %s    %s", sym.symbol, sym.help))
+	}
+	return cmdDecl{
+		name:    "status",
+		summary: "Print a one-line status summary for each git repository",
+		description: []string{
+			"Prints one line per repository with symbols summarizing work that is in flight.",
+		},
+		args: []argDecl{dirsArgDecl},
+		options: []optionDecl{
+			jobsOptDecl(&s.jobsOpt, "inspect"),
+		},
+		optionGroups: []optionGroup{s.discovery.group()},
+		sections: []helpSection{
+			{
+				title: "symbols",
+				lines: append(
+					symbols,
+					"",
+					"Repositories with operations in progress are labelled REBASING, AM, MERGING, CHERRY-PICKING,",
+					"REVERTING or BISECTING."),
+			},
+		},
+	}
+}
+
+func (s *statusCmd) run(args []string) int {
+
+	summaries, err := summarizeRepos(s.discovery.search(s.appCtx, args), s.appCtx.gitCLI, s.jobsOpt.Value, summaryOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	nameWidth := 0
+	for _, summary := range summaries {
+		if len(summary.Name) > nameWidth {
+			nameWidth = len(summary.Name)
+		}
+	}
+
+	colors := newColorizer(os.Stdout)
+	output := new(bytes.Buffer)
+	for _, summary := range summaries {
+		s.printStatus(output, colors, summary, nameWidth)
+	}
+
+	io.Copy(os.Stdout, output)
+	return 0
+}
+
+func (_ *statusCmd) printStatus(w io.Writer, colors colorizer, summary repoSummary, nameWidth int) {
+	symbols := new(strings.Builder)
+	for _, sym := range statusSymbols {
+		if sym.test(summary) {
+			symbols.WriteString(colors.paint(sym.color, sym.symbol))
+		} else {
+			symbols.WriteString(" ")
+		}
+	}
+	fmt.Fprintf(w, "%s  %-*s  %s", symbols, nameWidth, summary.Name, summary.Path)
+	for _, operation := range summary.Operations {
+		fmt.Fprintf(w, "  %s", colors.paint(colorRed, operationLabels[operation]))
+	}
+	fmt.Fprintf(w, "\n")
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ttd2089/ocg/internal/git"
+)
+
+// A repoSummary captures the state of a repository that ocg reports on.
+type repoSummary struct {
+	Name       string            `json:"name" yaml:"name"`
+	Path       string            `json:"path" yaml:"path"`
+	Root       string            `json:"root" yaml:"root"`
+	Bare       bool              `json:"bare,omitempty" yaml:"bare,omitempty"`
+	Operations []git.Operation   `json:"operations,omitempty" yaml:"operations,omitempty"`
+	Head       headSummary       `json:"head" yaml:"head"`
+	Dirty      bool              `json:"dirty" yaml:"dirty"`
+	WorkTree   *workTreeSummary  `json:"worktree,omitempty" yaml:"worktree,omitempty"`
+	Worktrees  []worktreeSummary `json:"worktrees,omitempty" yaml:"worktrees,omitempty"`
+	Stashes    int               `json:"stashes" yaml:"stashes"`
+	Branches   []branchSummary   `json:"branches" yaml:"branches"`
+}
+
+// A headSummary captures what is checked out in a repository.
+type headSummary struct {
+	Branch    string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	SHA       string `json:"sha,omitempty" yaml:"sha,omitempty"`
+	Detached  bool   `json:"detached" yaml:"detached"`
+	Reachable bool   `json:"reachable" yaml:"reachable"`
+}
+
+// A workTreeSummary captures the uncommitted changes in a repository.
+type workTreeSummary struct {
+	Staged     int            `json:"staged" yaml:"staged"`
+	Modified   int            `json:"modified" yaml:"modified"`
+	Untracked  int            `json:"untracked" yaml:"untracked"`
+	Conflicted int            `json:"conflicted" yaml:"conflicted"`
+	Files      *workTreeFiles `json:"files,omitempty" yaml:"files,omitempty"`
+}
+
+// A workTreeFiles captures the paths of the files with uncommitted changes in a repository.
+type workTreeFiles struct {
+	Staged     []string `json:"staged,omitempty" yaml:"staged,omitempty"`
+	Modified   []string `json:"modified,omitempty" yaml:"modified,omitempty"`
+	Untracked  []string `json:"untracked,omitempty" yaml:"untracked,omitempty"`
+	Conflicted []string `json:"conflicted,omitempty" yaml:"conflicted,omitempty"`
+}
+
+// A worktreeSummary captures what is checked out in another working tree attached to a
+// repository.
+type worktreeSummary struct {
+	Path     string `json:"path" yaml:"path"`
+	Branch   string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	SHA      string `json:"sha,omitempty" yaml:"sha,omitempty"`
+	Detached bool   `json:"detached" yaml:"detached"`
+}
+
+// summaryOptions control the level of detail captured in a repoSummary.
+type summaryOptions struct {
+
+	// files indicates whether the paths of files with uncommitted changes are captured.
+	files bool
+}
+
+// A branchSummary captures the state of a local branch that ocg reports on.
+type branchSummary struct {
+	Name     string           `json:"name" yaml:"name"`
+	SHA      string           `json:"sha" yaml:"sha"`
+	Current  bool             `json:"current,omitempty" yaml:"current,omitempty"`
+	Worktree string           `json:"worktree,omitempty" yaml:"worktree,omitempty"`
+	Tracking *trackingSummary `json:"remote,omitempty" yaml:"remote,omitempty"`
+	Merge    *mergeSummary    `json:"merge,omitempty" yaml:"merge,omitempty"`
+
+	// upstream indicates that the branch belongs to a bare repo with no remotes, which makes it
+	// the branch that others track rather than one that's expected to track another.
+	upstream bool
+}
+
+// A trackingSummary captures the state of a local branch relative to the branch it tracks.
+type trackingSummary struct {
+	Name   string `json:"name" yaml:"name"`
+	SHA    string `json:"sha" yaml:"sha"`
+	Ahead  int    `json:"ahead" yaml:"ahead"`
+	Behind int    `json:"behind" yaml:"behind"`
+}
+
+// A mergeSummary captures whether a branch has been merged into the default branch of its remote.
+type mergeSummary struct {
+	Target string         `json:"target" yaml:"target"`
+	State  git.MergeState `json:"state" yaml:"state"`
+}
+
+func summarizeRepo(repo git.Repo, options summaryOptions) (repoSummary, error) {
+	summary := repoSummary{
+		Name: repo.Name(),
+		Path: repo.Path(),
+		Bare: repo.Bare(),
+	}
+	operations, err := repo.Operations()
+	if err != nil {
+		return repoSummary{}, err
+	}
+	summary.Operations = operations
+	head, err := repo.Head()
+	if err != nil {
+		return repoSummary{}, err
+	}
+	summary.Head = headSummary(head)
+	if !repo.Bare() {
+		workTree, err := summarizeWorkTree(repo, options)
+		if err != nil {
+			return repoSummary{}, err
+		}
+		summary.WorkTree = workTree
+		summary.Dirty = !workTree.clean()
+	}
+	worktrees, err := summarizeWorktrees(repo)
+	if err != nil {
+		return repoSummary{}, err
+	}
+	summary.Worktrees = worktrees
+	stashes, err := repo.Stashes()
+	if err != nil {
+		return repoSummary{}, err
+	}
+	summary.Stashes = len(stashes)
+	branches, err := summarizeBranches(repo)
+	if err != nil {
+		return repoSummary{}, err
+	}
+	upstream := false
+	if repo.Bare() {
+		remotes, err := repo.Remotes()
+		if err != nil {
+			return repoSummary{}, err
+		}
+		upstream = len(remotes) == 0
+	}
+	for i := range branches {
+		branches[i].upstream = upstream
+		branches[i].Current = !head.Detached && branches[i].Name == head.Branch
+		for _, worktree := range worktrees {
+			if worktree.Branch == branches[i].Name {
+				branches[i].Worktree = worktree.Path
+			}
+		}
+	}
+	summary.Branches = branches
+	return summary, nil
+}
+
+func summarizeWorkTree(repo git.Repo, options summaryOptions) (*workTreeSummary, error) {
+	status, err := repo.Status()
+	if err != nil {
+		return nil, err
+	}
+	summary := &workTreeSummary{
+		Staged:     len(status.Staged),
+		Modified:   len(status.Modified),
+		Untracked:  len(status.Untracked),
+		Conflicted: len(status.Conflicted),
+	}
+	if options.files && !status.Clean() {
+		summary.Files = &workTreeFiles{
+			Staged:     status.Staged,
+			Modified:   status.Modified,
+			Untracked:  status.Untracked,
+			Conflicted: status.Conflicted,
+		}
+	}
+	return summary, nil
+}
+
+// summarizeWorktrees returns summaries of the working trees attached to repo other than the one at
+// the repo's own path.
+func summarizeWorktrees(repo git.Repo) ([]worktreeSummary, error) {
+	worktrees, err := repo.Worktrees()
+	if err != nil {
+		return nil, err
+	}
+	var summaries []worktreeSummary
+	for _, worktree := range worktrees {
+		if worktree.Bare || samePath(worktree.Path, repo.Path()) {
+			continue
+		}
+		summaries = append(summaries, worktreeSummary{
+			Path:     worktree.Path,
+			Branch:   worktree.Branch,
+			SHA:      worktree.SHA,
+			Detached: worktree.Detached,
+		})
+	}
+	return summaries, nil
+}
+
+func summarizeBranches(repo git.Repo) ([]branchSummary, error) {
+	branches, err := repo.LocalBranches()
+	if err != nil {
+		return nil, err
+	}
+	defaults, err := repo.DefaultBranches()
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]branchSummary, 0, len(branches))
+	for _, branch := range branches {
+		summary := branchSummary{
+			Name: branch.Name,
+			SHA:  branch.SHA,
+		}
+		if branch.Tracking != nil {
+			summary.Tracking = &trackingSummary{
+				Name:   branch.Tracking.Name,
+				SHA:    branch.Tracking.SHA,
+				Ahead:  branch.Ahead,
+				Behind: branch.Behind,
+			}
+		}
+		if target, ok := mergeTarget(branch, defaults); ok {
+			state, err := repo.MergeState(branch.Name, target.Name)
+			if err != nil {
+				return nil, err
+			}
+			summary.Merge = &mergeSummary{
+				Target: target.Name,
+				State:  state,
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// mergeTarget returns the default branch that branch is expected to be merged into: the default
+// branch of the remote it tracks or, for branches that don't track a remote, that of origin.
+func mergeTarget(branch git.LocalBranch, defaults map[string]git.Branch) (git.Branch, bool) {
+	remote := branch.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+	target, ok := defaults[remote]
+	return target, ok
+}
+
+// untracked returns a bool indicating whether the branch is expected to track a remote branch but
+// doesn't. Branches of bare repos without remotes aren't expected to.
+func (b branchSummary) untracked() bool {
+	return b.Tracking == nil && !b.upstream
+}
+
+func (w workTreeSummary) clean() bool {
+	return w.Staged == 0 && w.Modified == 0 && w.Untracked == 0 && w.Conflicted == 0
+}
+
+// clean returns a bool indicating whether the repo has no unfinished work.
+func (r repoSummary) clean() bool {
+	return len(r.unfinishedWork()) == 0
+}
+
+// unfinishedWork returns terse descriptions of the work in the repo that is unfinished: operations
+// in progress, uncommitted or stashed changes, commits only reachable from a detached HEAD, and
+// branches that are out of sync with their remote or unmerged.
+func (r repoSummary) unfinishedWork() []string {
+	var reasons []string
+	for _, operation := range r.Operations {
+		reasons = append(reasons, fmt.Sprintf("%s in progress", operation))
+	}
+	if r.Dirty {
+		reasons = append(reasons, "uncommitted changes")
+	}
+	if r.Stashes > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d %s", r.Stashes, plural(r.Stashes, "stash", "stashes")))
+	}
+	if r.Head.lost() {
+		reasons = append(reasons, fmt.Sprintf("detached HEAD at %s is not on any branch", shortSHA(r.Head.SHA)))
+	}
+	for _, branch := range r.Branches {
+		reasons = append(reasons, branch.unfinishedWork()...)
+	}
+	return reasons
+}
+
+func (h headSummary) lost() bool {
+	return h.Detached && !h.Reachable
+}
+
+// unfinishedWork returns terse descriptions of the ways that the branch is out of sync with its
+// remote or unmerged.
+func (b branchSummary) unfinishedWork() []string {
+	var reasons []string
+	if b.untracked() {
+		reasons = append(reasons, fmt.Sprintf("branch %s does not track a remote branch", b.Name))
+	} else if b.Tracking != nil {
+		if b.Tracking.Ahead > 0 {
+			reasons = append(reasons, fmt.Sprintf(
+				"branch %s is %d %s ahead of %s",
+				b.Name,
+				b.Tracking.Ahead,
+				plural(b.Tracking.Ahead, "commit", "commits"),
+				b.Tracking.Name))
+		}
+		if b.Tracking.Behind > 0 {
+			reasons = append(reasons, fmt.Sprintf(
+				"branch %s is %d %s behind %s",
+				b.Name,
+				b.Tracking.Behind,
+				plural(b.Tracking.Behind, "commit", "commits"),
+				b.Tracking.Name))
+		}
+	}
+	if b.unmerged() {
+		reasons = append(reasons, fmt.Sprintf("branch %s is not merged into %s", b.Name, b.Merge.Target))
+	}
+	return reasons
+}
+
+func (b branchSummary) ahead() bool {
+	return b.Tracking != nil && b.Tracking.Ahead > 0 && b.Tracking.Behind == 0
+}
+
+func (b branchSummary) behind() bool {
+	return b.Tracking != nil && b.Tracking.Behind > 0 && b.Tracking.Ahead == 0
+}
+
+func (b branchSummary) diverged() bool {
+	return b.Tracking != nil && b.Tracking.Ahead > 0 && b.Tracking.Behind > 0
+}
+
+func (b branchSummary) unmerged() bool {
+	return b.Merge != nil && b.Merge.State == git.Unmerged
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ttd2089/ocg/internal/git"
+	"github.com/ttd2089/ocg/internal/opts"
+)
+
+func newSyncCmd(appCtx appContext) cmd {
+	return &syncCmd{
+		discovery: newDiscoveryOpts(),
+		dryRunOpt: opts.FlagOpt{
+			OptionName: opts.OptionName{
+				LongName:  "dry-run",
+				ShortName: 'n',
+			},
+		},
+		jobsOpt: newJobsOpt(),
+		appCtx:  appCtx,
+	}
+}
+
+type syncCmd struct {
+	discovery *discoveryOpts
+	dryRunOpt opts.FlagOpt
+	jobsOpt   opts.IntOpt
+	appCtx    appContext
+}
+
+// A syncAction describes the fast-forward of a single branch.
+type syncAction struct {
+	branch string
+	from   string
+	to     string
+
+	// skip is the reason the branch was not fast-forwarded, if it wasn't.
+	skip string
+
+	// err is the error encountered while fast-forwarding the branch, if any.
+	err error
+}
+
+func (s *syncCmd) declare() cmdDecl {
+	return cmdDecl{
+		name:    "sync",
+		summary: "Fast-forward branches that are behind the branches they track",
+		description: []string{
+			"Fast-forwards every local branch that is strictly behind the branch it tracks in every repository found in <dir>. Branches that are not checked out are updated without touching the working tree. Checked out branches are only updated when the working tree is clean, and only from the worktree they're checked out in. Branches that have diverged from the branch they track are skipped.",
+			"Run ocg fetch first to sync with the latest state of the remotes.",
+		},
+		args: []argDecl{dirsArgDecl},
+		options: []optionDecl{
+			{
+				opt:  &s.dryRunOpt,
+				help: "Print the branches that would be updated without updating them",
+			},
+			jobsOptDecl(&s.jobsOpt, "sync"),
+		},
+		optionGroups: []optionGroup{s.discovery.group()},
+	}
+}
+
+func (s *syncCmd) run(args []string) int {
+
+	// Branches that aren't checked out belong to the repository rather than a worktree so only
+	// the first worktree of each repository to be processed updates them.
+	var claims repoClaims
+	results, err := processRepos(
+		s.discovery.search(s.appCtx, args),
+		s.appCtx.gitCLI,
+		s.jobsOpt.Value,
+		func(repo git.Repo) ([]syncAction, error) {
+			return s.syncRepo(repo, claims.claim(repo))
+		})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	status := 0
+	output := new(bytes.Buffer)
+	for _, result := range results {
+		if result.err != nil {
+			fmt.Fprintf(output, "%s\n  error: %v\n", result.repo.Path(), result.err)
+			status = 1
+			continue
+		}
+		if len(result.value) == 0 {
+			continue
+		}
+		fmt.Fprintf(output, "%s\n", result.repo.Path())
+		for _, action := range result.value {
+			s.printAction(output, action)
+			if action.err != nil {
+				status = 1
+			}
+		}
+	}
+
+	io.Copy(os.Stdout, output)
+	return status
+}
+
+// syncRepo fast-forwards the branches in repo that are strictly behind the branches they track.
+// The checked out branch is always considered while other branches are only considered when
+// includeRefs is true. Branches checked out in other worktrees are left to those worktrees.
+func (s *syncCmd) syncRepo(repo git.Repo, includeRefs bool) ([]syncAction, error) {
+	branches, err := repo.LocalBranches()
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	checkedOut, err := checkedOutElsewhere(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []syncAction
+	for _, branch := range branches {
+		if branch.Tracking == nil || branch.Behind == 0 {
+			continue
+		}
+		current := !repo.Bare() && !head.Detached && head.Branch == branch.Name
+		if !current && (!includeRefs || checkedOut[branch.Name]) {
+			continue
+		}
+		action := syncAction{
+			branch: branch.Name,
+			from:   branch.SHA,
+			to:     branch.Tracking.SHA,
+		}
+		switch {
+		case branch.Ahead > 0:
+			action.skip = fmt.Sprintf("diverged from %s", branch.Tracking.Name)
+		case current:
+			action.skip, err = workTreeBusy(repo)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if action.skip == "" && !s.dryRunOpt.Value {
+			if current {
+				action.err = repo.FastForward(branch.Tracking.Name)
+			} else {
+				action.err = repo.UpdateBranch(branch.Name, branch.Tracking.SHA, branch.SHA)
+			}
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// checkedOutElsewhere returns the set of branches checked out in the other worktrees attached to
+// repo.
+func checkedOutElsewhere(repo git.Repo) (map[string]bool, error) {
+	worktrees, err := repo.Worktrees()
+	if err != nil {
+		return nil, err
+	}
+	checkedOut := map[string]bool{}
+	for _, worktree := range worktrees {
+		if worktree.Branch != "" && !samePath(worktree.Path, repo.Path()) {
+			checkedOut[worktree.Branch] = true
+		}
+	}
+	return checkedOut, nil
+}
+
+// workTreeBusy returns the reason the working tree of repo can't be updated, or an empty string if
+// it can.
+func workTreeBusy(repo git.Repo) (string, error) {
+	operations, err := repo.Operations()
+	if err != nil {
+		return "", err
+	}
+	if len(operations) > 0 {
+		return fmt.Sprintf("%s in progress", operations[0]), nil
+	}
+	status, err := repo.Status()
+	if err != nil {
+		return "", err
+	}
+	if !status.Clean() {
+		return "working tree has uncommitted changes", nil
+	}
+	return "", nil
+}
+
+func (s *syncCmd) printAction(w io.Writer, action syncAction) {
+	switch {
+	case action.skip != "":
+		fmt.Fprintf(w, "  skipped %s: %s\n", action.branch, action.skip)
+	case action.err != nil:
+		fmt.Fprintf(w, "  failed %s: %v\n", action.branch, action.err)
+	case s.dryRunOpt.Value:
+		fmt.Fprintf(w, "  would fast-forward %s %s..%s\n", action.branch, shortSHA(action.from), shortSHA(action.to))
+	default:
+		fmt.Fprintf(w, "  fast-forwarded %s %s..%s\n", action.branch, shortSHA(action.from), shortSHA(action.to))
+	}
+}
+
+// shortSHA returns the abbreviated form of a commit hash.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSyncRepo(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		setup       func(g gitFunc)
+		includeRefs bool
+		dryRun      bool
+		expected    []string
+
+		// synced are the branches expected to point at the branches they track afterwards.
+		synced []string
+	}{
+		{
+			name: "Updates branches that aren't checked out",
+			setup: func(g gitFunc) {
+				makeBehind(g, "topic")
+			},
+			includeRefs: true,
+			expected:    []string{"topic"},
+			synced:      []string{"main", "topic"},
+		},
+		{
+			name: "Updates the checked out branch when the working tree is clean",
+			setup: func(g gitFunc) {
+				makeBehind(g, "topic")
+				g("checkout", "-q", "topic")
+			},
+			expected: []string{"topic"},
+			synced:   []string{"main", "topic"},
+		},
+		{
+			name: "Skips diverged branches",
+			setup: func(g gitFunc) {
+				makeBehind(g, "topic")
+				g("checkout", "-q", "topic")
+				commitFile(g, "local.txt", "local\n")
+				g("checkout", "-q", "main")
+			},
+			includeRefs: true,
+			expected:    []string{"topic: diverged from origin/topic"},
+			synced:      []string{"main"},
+		},
+		{
+			name: "Skips the checked out branch when the working tree is dirty",
+			setup: func(g gitFunc) {
+				makeBehind(g, "topic")
+				g("checkout", "-q", "topic")
+				path := strings.TrimSpace(g("rev-parse", "--show-toplevel"))
+				if err := os.WriteFile(filepath.Join(path, "README"), []byte("changed\n"), 0o644); err != nil {
+					panic(err)
+				}
+			},
+			includeRefs: true,
+			expected:    []string{"topic: working tree has uncommitted changes"},
+			synced:      []string{"main"},
+		},
+		{
+			name: "Leaves branches checked out in other worktrees to those worktrees",
+			setup: func(g gitFunc) {
+				makeBehind(g, "topic")
+				path := filepath.Join(filepath.Dir(g("rev-parse", "--show-toplevel")), "worktree")
+				g("worktree", "add", "-q", path, "topic")
+			},
+			includeRefs: true,
+			expected:    []string{},
+			synced:      []string{"main"},
+		},
+		{
+			name: "Leaves branches that aren't checked out alone without includeRefs",
+			setup: func(g gitFunc) {
+				makeBehind(g, "topic")
+			},
+			expected: []string{},
+			synced:   []string{"main"},
+		},
+		{
+			name: "Updates nothing on a dry run",
+			setup: func(g gitFunc) {
+				makeBehind(g, "topic")
+				makeBehind(g, "current")
+				g("checkout", "-q", "current")
+			},
+			includeRefs: true,
+			dryRun:      true,
+			expected:    []string{"current", "topic"},
+			synced:      []string{"main"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, g := newTestRepo(t)
+			tt.setup(g)
+			status := g("status", "--porcelain")
+
+			underTest := newSyncCmd(appContext{}).(*syncCmd)
+			underTest.dryRunOpt.Value = tt.dryRun
+			actions, err := underTest.syncRepo(openTestRepo(t, path), tt.includeRefs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual := []string{}
+			for _, action := range actions {
+				if action.err != nil {
+					t.Fatalf("unexpected error syncing %s: %v", action.branch, action.err)
+				}
+				description := action.branch
+				if action.skip != "" {
+					description += ": " + action.skip
+				}
+				actual = append(actual, description)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %q; got %q", tt.expected, actual)
+			}
+
+			synced := []string{}
+			refs := g("for-each-ref", "--format=%(refname:short) %(objectname) %(upstream:short)", "refs/heads")
+			for _, line := range strings.Split(strings.TrimSpace(refs), "\n") {
+				fields := strings.Fields(line)
+				if len(fields) == 3 && g("rev-parse", fields[2]) == fields[1]+"\n" {
+					synced = append(synced, fields[0])
+				}
+			}
+			if !reflect.DeepEqual(synced, tt.synced) {
+				t.Errorf("expected %q to be synced; got %q", tt.synced, synced)
+			}
+			if after := g("status", "--porcelain"); after != status {
+				t.Errorf("expected the working tree to be unchanged; got '%s' before and '%s' after", status, after)
+			}
+		})
+	}
+}
+
+// makeBehind creates the named branch tracking a remote branch that is one commit ahead of it.
+func makeBehind(g gitFunc, name string) {
+	g("checkout", "-q", "-b", name)
+	g("push", "-q", "-u", "origin", name)
+	commitFile(g, name+".txt", name+"\n")
+	g("push", "-q", "origin", name)
+	g("reset", "-q", "--hard", "HEAD~1")
+	g("checkout", "-q", "main")
+}
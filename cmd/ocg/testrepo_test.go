@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ttd2089/ocg/internal/git"
+	"github.com/ttd2089/shgit"
+)
+
+// A gitFunc runs a git command in a test repo and returns its output.
+type gitFunc func(args ...string) string
+
+// newTestRepo creates a clone of a bare repo with one commit on main, which the clone has checked
+// out and tracks, and returns the path of the clone along with a gitFunc that runs commands in it.
+// The bare repo is the clone's origin.
+func newTestRepo(t *testing.T) (string, gitFunc) {
+	t.Helper()
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "ocg")
+	t.Setenv("GIT_AUTHOR_EMAIL", "ocg@localhost")
+	t.Setenv("GIT_COMMITTER_NAME", "ocg")
+	t.Setenv("GIT_COMMITTER_EMAIL", "ocg@localhost")
+
+	dir := t.TempDir()
+	newGitFunc(t, dir)("init", "-q", "--bare", "-b", "main", "origin.git")
+	newGitFunc(t, dir)("init", "-q", "-b", "main", "repo")
+	path := filepath.Join(dir, "repo")
+	g := newGitFunc(t, path)
+	g("remote", "add", "origin", filepath.Join(dir, "origin.git"))
+	commitFile(g, "README", "test\n")
+	g("push", "-q", "-u", "origin", "main")
+	return path, g
+}
+
+// newGitFunc returns a gitFunc that runs commands in the directory at path.
+func newGitFunc(t *testing.T, path string) gitFunc {
+	return func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", path}, args...)...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return string(output)
+	}
+}
+
+// openTestRepo returns the git.Repo at path.
+func openTestRepo(t *testing.T, path string) git.Repo {
+	t.Helper()
+	repo, err := git.NewRepo(path, shgit.NewCLI())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return repo
+}
+
+// commitFile writes content to the named file in the test repo and commits it.
+func commitFile(g gitFunc, name, content string) {
+	path := strings.TrimSpace(g("rev-parse", "--show-toplevel"))
+	if err := os.WriteFile(filepath.Join(path, name), []byte(content), 0o644); err != nil {
+		panic(err)
+	}
+	g("add", "--", name)
+	g("commit", "-q", "-m", "update "+name)
+}
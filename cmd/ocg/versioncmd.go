@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+)
+
+// go build -ldflags="-X 'main.OCGVersion=<version>'"
+var OCGVersion = "0.0.0.dev"
+
+func newVersionCmd(appCtx appContext) cmd {
+	return &versionCmd{}
+}
+
+type versionCmd struct{}
+
+func (_ *versionCmd) declare() cmdDecl {
+	return cmdDecl{
+		name:          "version",
+		summary:       "Print OCG version information",
+		ignoresConfig: true,
+	}
+}
+
+func (_ *versionCmd) run(args []string) int {
+	fmt.Printf("ocg version %s\n", OCGVersion)
+	return 0
+}
@@ -5,6 +5,7 @@ go 1.19
 require (
 	github.com/ttd2089/shgit v0.0.0-20221204120552-96f9617aaabe
 	github.com/ttd2089/tyers v0.0.0-20221203135342-e884c3d5e9d9
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require github.com/ttd2089/shellout v0.0.0-20221203132534-0b33d815451e // indirect
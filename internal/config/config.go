@@ -0,0 +1,205 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/ttd2089/ocg/internal/ignore"
+	"github.com/ttd2089/tyers"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidConfig is returned when a config file can't be parsed or contains invalid values.
+var ErrInvalidConfig error = errors.New("ErrInvalidConfig")
+
+// A Config holds the user's preferences from the ocg config file. The zero value represents an
+// empty config file. Settings that provide the defaults of command line options are kept as the
+// strings they're written as and parsed by the options, see Lookup.
+type Config struct {
+
+	// Roots are the directories to search for repositories when no directory is given on the
+	// command line.
+	Roots []string `yaml:"roots"`
+
+	// Ignore holds glob patterns for directories that are never searched for repositories. Patterns
+	// containing a path separator are matched against the whole path and other patterns are matched
+	// against the directory name.
+	Ignore []string `yaml:"ignore"`
+
+	// Format is the default output format of ocg list.
+	Format string `yaml:"format"`
+
+	// Jobs is the default number of repositories processed concurrently.
+	Jobs string `yaml:"jobs"`
+
+	// Timeout is the default time limit of the commands that talk to remotes, e.g. 30s.
+	Timeout string `yaml:"timeout"`
+
+	// MaxDepth is the default maximum depth below each root to search for repositories.
+	MaxDepth string `yaml:"max_depth"`
+
+	// FollowSymlinks is the default for whether symlinks to directories are searched.
+	FollowSymlinks string `yaml:"follow_symlinks"`
+
+	// ProtectedBranches are the names of remote branches that ocg push won't push to unless
+	// explicitly allowed. A nil slice means the built-in defaults apply.
+	ProtectedBranches []string `yaml:"protected_branches"`
+
+	// Repos holds overrides for the repositories whose paths match their Path patterns.
+	Repos []RepoConfig `yaml:"repos"`
+}
+
+// A RepoConfig overrides the config for the repositories whose paths match Path.
+type RepoConfig struct {
+
+	// Path is a glob pattern matched against the whole path of a repository.
+	Path string `yaml:"path"`
+
+	// Ignore indicates whether the matching repositories are skipped.
+	Ignore bool `yaml:"ignore"`
+
+	// ProtectedBranches replaces Config.ProtectedBranches for the matching repositories when it's
+	// not nil.
+	ProtectedBranches []string `yaml:"protected_branches"`
+}
+
+// Path returns the path of the config file: $XDG_CONFIG_HOME/ocg/config.yaml, or
+// ~/.config/ocg/config.yaml when XDG_CONFIG_HOME isn't set.
+func Path() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if !filepath.IsAbs(configHome) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine config file path: %w", err)
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "ocg", "config.yaml"), nil
+}
+
+// Load reads the config file at path. An empty Config is returned if the file doesn't exist.
+// Relative paths in the file are resolved against the directory containing it and a leading ~ is
+// replaced with the user's home directory.
+func Load(path string) (Config, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config file '%s': %w", path, err)
+	}
+	config, err := parse(content, filepath.Dir(path))
+	if err != nil {
+		return Config{}, tyers.Errorf(ErrInvalidConfig, "invalid config file '%s': %v", path, err)
+	}
+	return config, nil
+}
+
+func parse(content []byte, baseDir string) (Config, error) {
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && err != io.EOF {
+		return Config{}, err
+	}
+
+	var err error
+	for i, root := range config.Roots {
+		if config.Roots[i], err = resolvePath(root, baseDir); err != nil {
+			return Config{}, err
+		}
+	}
+	for i, pattern := range config.Ignore {
+		if config.Ignore[i], err = resolvePattern(pattern, baseDir); err != nil {
+			return Config{}, err
+		}
+	}
+	for i, repo := range config.Repos {
+		if repo.Path == "" {
+			return Config{}, fmt.Errorf("repos[%d]: path is required", i)
+		}
+		if _, err := filepath.Match(repo.Path, ""); err != nil {
+			return Config{}, fmt.Errorf("invalid pattern '%s': %w", repo.Path, err)
+		}
+		if config.Repos[i].Path, err = resolvePath(repo.Path, baseDir); err != nil {
+			return Config{}, err
+		}
+	}
+	return config, nil
+}
+
+// resolvePath expands a leading ~ in path and makes it absolute relative to baseDir.
+func resolvePath(path, baseDir string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to expand '%s': %w", path, err)
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	path = filepath.FromSlash(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// resolvePattern validates a glob pattern and resolves it like a path if it contains a path
+// separator or a leading ~.
+func resolvePattern(pattern, baseDir string) (string, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return "", fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+	}
+	if pattern == "~" || strings.ContainsRune(filepath.FromSlash(pattern), filepath.Separator) {
+		return resolvePath(pattern, baseDir)
+	}
+	return pattern, nil
+}
+
+// Lookup returns the value of the setting with the given key, as it's spelled in the config file,
+// and a bool indicating whether it's set. Only settings with a single value can be looked up.
+func (c Config) Lookup(key string) (string, bool) {
+	value := reflect.ValueOf(c)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.Tag.Get("yaml") == key && field.Type.Kind() == reflect.String {
+			setting := value.Field(i).String()
+			return setting, setting != ""
+		}
+	}
+	return "", false
+}
+
+// Ignored returns a bool indicating whether the directory at path should not be searched for
+// repositories.
+func (c Config) Ignored(path string) bool {
+	for _, pattern := range c.Ignore {
+		if ignore.MatchGlob(pattern, path) {
+			return true
+		}
+	}
+	for _, repo := range c.Repos {
+		if repo.Ignore && ignore.MatchGlob(repo.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Protected returns the protected branch names for the repository at path, or nil if the
+// built-in defaults apply.
+func (c Config) Protected(path string) []string {
+	protected := c.ProtectedBranches
+	for _, repo := range c.Repos {
+		if repo.ProtectedBranches != nil && ignore.MatchGlob(repo.Path, path) {
+			protected = repo.ProtectedBranches
+		}
+	}
+	return protected
+}
@@ -0,0 +1,270 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+
+	t.Run("Uses XDG_CONFIG_HOME when set", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "/xdg")
+		path, err := Path()
+		if err != nil {
+			t.Fatalf("expected no error; got %v", err)
+		}
+		if expected := filepath.Join("/xdg", "ocg", "config.yaml"); path != expected {
+			t.Fatalf("expected '%s'; got '%s'", expected, path)
+		}
+	})
+
+	t.Run("Falls back to ~/.config", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "")
+		t.Setenv("HOME", "/home/ocg")
+		path, err := Path()
+		if err != nil {
+			t.Fatalf("expected no error; got %v", err)
+		}
+		if expected := filepath.Join("/home/ocg", ".config", "ocg", "config.yaml"); path != expected {
+			t.Fatalf("expected '%s'; got '%s'", expected, path)
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+
+	t.Setenv("HOME", "/home/ocg")
+
+	tests := []struct {
+		name           string
+		content        string
+		expectedConfig Config
+		expectErr      bool
+	}{
+		{
+			name:           "Loads empty file",
+			content:        "",
+			expectedConfig: Config{},
+		},
+		{
+			name: "Resolves roots",
+			content: `
+roots:
+  - ~/src
+  - /abs/src
+  - rel/src
+`,
+			expectedConfig: Config{
+				Roots: []string{
+					filepath.Join("/home/ocg", "src"),
+					filepath.Join("/abs", "src"),
+					filepath.Join("/config", "rel", "src"),
+				},
+			},
+		},
+		{
+			name: "Resolves ignore patterns with separators",
+			content: `
+ignore:
+  - node_modules
+  - ~/src/archive/*
+`,
+			expectedConfig: Config{
+				Ignore: []string{
+					"node_modules",
+					filepath.Join("/home/ocg", "src", "archive", "*"),
+				},
+			},
+		},
+		{
+			name: "Loads format and protected branches",
+			content: `
+format: json
+protected_branches: [main, release]
+`,
+			expectedConfig: Config{
+				Format:            "json",
+				ProtectedBranches: []string{"main", "release"},
+			},
+		},
+		{
+			name: "Loads option defaults as strings",
+			content: `
+jobs: 4
+timeout: 30s
+max_depth: 2
+follow_symlinks: true
+`,
+			expectedConfig: Config{
+				Jobs:           "4",
+				Timeout:        "30s",
+				MaxDepth:       "2",
+				FollowSymlinks: "true",
+			},
+		},
+		{
+			name: "Resolves repo overrides",
+			content: `
+repos:
+  - path: ~/src/scratch
+    ignore: true
+  - path: work/*
+    protected_branches: []
+`,
+			expectedConfig: Config{
+				Repos: []RepoConfig{
+					{
+						Path:   filepath.Join("/home/ocg", "src", "scratch"),
+						Ignore: true,
+					},
+					{
+						Path:              filepath.Join("/config", "work", "*"),
+						ProtectedBranches: []string{},
+					},
+				},
+			},
+		},
+		{
+			name:      "Rejects unknown fields",
+			content:   "rootz: [~/src]",
+			expectErr: true,
+		},
+		{
+			name:      "Rejects repo overrides without a path",
+			content:   "repos: [{ignore: true}]",
+			expectErr: true,
+		},
+		{
+			name:      "Rejects invalid patterns",
+			content:   "ignore: ['[']",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := parse([]byte(tt.content), "/config")
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error: %v; got %v", tt.expectErr, err)
+			}
+			if err == nil && !reflect.DeepEqual(config, tt.expectedConfig) {
+				t.Fatalf("expected config %#v; got %#v", tt.expectedConfig, config)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	config, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatalf("expected no error; got %v", err)
+	}
+	if !reflect.DeepEqual(config, Config{}) {
+		t.Fatalf("expected empty config; got %#v", config)
+	}
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("roots: ["), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Load(path)
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected error %v; got %v", ErrInvalidConfig, err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+
+	config := Config{
+		Roots:    []string{"/src"},
+		Format:   "json",
+		MaxDepth: "2",
+	}
+
+	tests := []struct {
+		key           string
+		expectedValue string
+		expectedOK    bool
+	}{
+		{"format", "json", true},
+		{"max_depth", "2", true},
+		{"jobs", "", false},
+		{"roots", "", false},
+		{"unknown", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			value, ok := config.Lookup(tt.key)
+			if value != tt.expectedValue || ok != tt.expectedOK {
+				t.Fatalf("expected ('%s', %v); got ('%s', %v)", tt.expectedValue, tt.expectedOK, value, ok)
+			}
+		})
+	}
+}
+
+func TestIgnored(t *testing.T) {
+
+	config := Config{
+		Ignore: []string{"node_modules", "/src/archive/*"},
+		Repos: []RepoConfig{
+			{Path: "/src/scratch", Ignore: true},
+			{Path: "/src/kept", Ignore: false},
+		},
+	}
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"/src/app/node_modules", true},
+		{"/src/archive/old", true},
+		{"/src/archive", false},
+		{"/src/scratch", true},
+		{"/src/kept", false},
+		{"/src/app", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if actual := config.Ignored(tt.path); actual != tt.expected {
+				t.Fatalf("expected %v; got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestProtected(t *testing.T) {
+
+	config := Config{
+		ProtectedBranches: []string{"main"},
+		Repos: []RepoConfig{
+			{Path: "/src/work/*", ProtectedBranches: []string{"main", "release"}},
+			{Path: "/src/work/sandbox", ProtectedBranches: []string{}},
+			{Path: "/src/ignored", Ignore: true},
+		},
+	}
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"/src/app", []string{"main"}},
+		{"/src/work/api", []string{"main", "release"}},
+		{"/src/work/sandbox", []string{}},
+		{"/src/ignored", []string{"main"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if actual := config.Protected(tt.path); !reflect.DeepEqual(actual, tt.expected) {
+				t.Fatalf("expected %v; got %v", tt.expected, actual)
+			}
+		})
+	}
+}
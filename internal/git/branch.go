@@ -14,6 +14,29 @@ type Branch struct {
 type LocalBranch struct {
 	Branch
 
-	// RemoteBranch is the Branch that a LocalBranch is tracking.
+	// Remote is the name of the remote that the LocalBranch's upstream belongs to.
+	Remote string
+
+	// RemoteBranch is the name of the branch on the Remote that the LocalBranch's upstream is
+	// fetched from, e.g. main when the upstream is origin/main.
+	RemoteBranch string
+
+	// Tracking is the Branch that a LocalBranch is tracking.
 	Tracking *Branch
+
+	// Ahead is the number of commits on the LocalBranch that are not on the Tracking branch.
+	Ahead int
+
+	// Behind is the number of commits on the Tracking branch that are not on the LocalBranch.
+	Behind int
+
+	// Gone indicates that the LocalBranch is configured to track a remote branch that no longer
+	// exists, e.g. because it was deleted after being merged.
+	Gone bool
+}
+
+// Diverged returns a bool indicating whether the LocalBranch and its Tracking branch each contain
+// commits that the other does not.
+func (b LocalBranch) Diverged() bool {
+	return b.Ahead > 0 && b.Behind > 0
 }
@@ -0,0 +1,74 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ttd2089/shgit"
+	"github.com/ttd2089/tyers"
+)
+
+// NewTimeoutCLI returns a shgit.CLI that kills git commands, along with any processes they
+// started, when they run for longer than timeout. A timeout of 0 means commands never time out.
+//
+// Commands run by the returned CLI can't prompt for credentials since a prompt would block until
+// the command times out.
+func NewTimeoutCLI(timeout time.Duration) shgit.CLI {
+	return &timeoutCLI{
+		timeout: timeout,
+	}
+}
+
+type timeoutCLI struct {
+	timeout time.Duration
+}
+
+func (c *timeoutCLI) Run(cmd ...string) (string, error) {
+	proc := exec.Command("git", cmd...)
+	if proc.Err != nil {
+		return "", tyers.As(shgit.ErrGitNotFound, proc.Err)
+	}
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	proc.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	proc.Stdout = stdout
+	proc.Stderr = stderr
+	setProcessGroup(proc)
+
+	if err := proc.Start(); err != nil {
+		return "", tyers.As(shgit.ErrGitCommandFailed, err)
+	}
+	var timedOut atomic.Bool
+	if c.timeout > 0 {
+		timer := time.AfterFunc(c.timeout, func() {
+			timedOut.Store(true)
+			killProcessGroup(proc)
+		})
+		defer timer.Stop()
+	}
+
+	err := proc.Wait()
+	if timedOut.Load() {
+		return "", tyers.Errorf(
+			ErrGitCommandTimedOut,
+			"git %s timed out after %s",
+			strings.Join(cmd, " "),
+			c.timeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return "", &shgit.CLIError{
+			ExitCode: exitErr.ExitCode(),
+			Stdout:   stdout.String(),
+			Stderr:   stderr.String(),
+		}
+	}
+	if err != nil {
+		return "", tyers.As(shgit.ErrGitCommandFailed, err)
+	}
+	return stdout.String(), nil
+}
@@ -8,3 +8,6 @@ var ErrNotAGitRepo error = errors.New("ErrNotAGitRepo")
 
 // ErrFailedGitCommand is returned when ocg failed to execute a git command process.
 var ErrFailedGitCommand error = errors.New("ErrFailedGitCommand")
+
+// ErrGitCommandTimedOut is returned when a git command was killed for running longer than allowed.
+var ErrGitCommandTimedOut error = errors.New("ErrGitCommandTimedOut")
@@ -0,0 +1,67 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Head describes what is checked out in a repository's working tree.
+type Head struct {
+
+	// Branch is the name of the checked out branch. Branch is empty when HEAD is detached.
+	Branch string
+
+	// SHA is the hash of the commit HEAD points to. SHA is empty when the checked out branch has
+	// no commits.
+	SHA string
+
+	// Detached indicates whether HEAD points directly to a commit rather than a branch.
+	Detached bool
+
+	// Reachable indicates whether the commit HEAD points to is reachable from any local or remote
+	// branch. Commits that aren't reachable from a branch are lost when HEAD moves.
+	Reachable bool
+}
+
+func (r *repo) Head() (Head, error) {
+	var head Head
+	output, err := r.git("symbolic-ref", "--quiet", "--short", "HEAD")
+	if isExitCode(err, 1) {
+		head.Detached = true
+	} else if err != nil {
+		return Head{}, r.headErr(err)
+	} else {
+		head.Branch = strings.TrimSpace(output)
+	}
+
+	output, err = r.git("rev-parse", "--verify", "--quiet", "HEAD")
+	if isExitCode(err, 1) {
+		return head, nil
+	}
+	if err != nil {
+		return Head{}, r.headErr(err)
+	}
+	head.SHA = strings.TrimSpace(output)
+
+	if !head.Detached {
+		head.Reachable = true
+		return head, nil
+	}
+	output, err = r.git(
+		"for-each-ref",
+		"--count=1",
+		"--format=%(refname)",
+		"--contains",
+		head.SHA,
+		"refs/heads",
+		"refs/remotes")
+	if err != nil {
+		return Head{}, r.headErr(err)
+	}
+	head.Reachable = strings.TrimSpace(output) != ""
+	return head, nil
+}
+
+func (r *repo) headErr(err error) error {
+	return fmt.Errorf("failed to get HEAD of repo '%s': %v", r.Path(), err)
+}
@@ -0,0 +1,198 @@
+package git
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ttd2089/shgit"
+)
+
+// A MergeState describes whether the changes on one branch have been integrated into another.
+type MergeState int
+
+const (
+	// Unmerged indicates that the branch contains changes that are not in the target.
+	Unmerged MergeState = iota
+
+	// Merged indicates that the tip of the branch is reachable from the target.
+	Merged
+
+	// SquashMerged indicates that the tip of the branch is not reachable from the target but the
+	// target contains equivalent changes, e.g. because the branch was squash or rebase merged.
+	SquashMerged
+)
+
+func (s MergeState) String() string {
+	switch s {
+	case Merged:
+		return "merged"
+	case SquashMerged:
+		return "squash-merged"
+	default:
+		return "unmerged"
+	}
+}
+
+// MarshalText encodes a MergeState as its string representation.
+func (s MergeState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// defaultBranchFallbacks are the branch names that are checked, in order, to determine the
+// default branch of a remote that has no HEAD ref.
+var defaultBranchFallbacks = []string{"main", "master"}
+
+func (r *repo) DefaultBranches() (map[string]Branch, error) {
+	remotes, err := r.Remotes()
+	if err != nil {
+		return nil, err
+	}
+	output, err := r.git(
+		"for-each-ref",
+		"--format=%(refname)%09%(objectname)%09%(symref)",
+		"refs/remotes")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote branches in repo '%s': %v", r.Path(), err)
+	}
+
+	refs := map[string][]string{}
+	for _, tokens := range tokenizeLines(output, "\t") {
+		if len(tokens) != 3 {
+			line := strings.Join(tokens, " ")
+			return nil, fmt.Errorf("repo.DefaultBranches(): unexpected output from git command: %s", line)
+		}
+		refs[strings.TrimPrefix(tokens[0], "refs/remotes/")] = tokens[1:]
+	}
+
+	defaults := map[string]Branch{}
+	for _, remote := range remotes {
+		candidates := []string{}
+		if head, ok := refs[remote+"/HEAD"]; ok && head[1] != "" {
+			candidates = append(candidates, strings.TrimPrefix(head[1], "refs/remotes/"))
+		}
+		for _, name := range defaultBranchFallbacks {
+			candidates = append(candidates, remote+"/"+name)
+		}
+		for _, name := range candidates {
+			if ref, ok := refs[name]; ok {
+				defaults[remote] = Branch{
+					Name: name,
+					SHA:  ref[0],
+				}
+				break
+			}
+		}
+	}
+	return defaults, nil
+}
+
+func (r *repo) MergeState(branch, target string) (MergeState, error) {
+
+	// Full ref names are used so that tags with the same names as the branches aren't used
+	// instead.
+	branchRef, targetRef := "refs/heads/"+branch, "refs/remotes/"+target
+
+	_, err := r.git("merge-base", "--is-ancestor", branchRef, targetRef)
+	if err == nil {
+		return Merged, nil
+	}
+	if !isExitCode(err, 1) {
+		return Unmerged, r.mergeStateErr(branch, target, err)
+	}
+
+	// If every commit on the branch has an equivalent commit on the target then the branch was
+	// rebased or cherry-picked onto the target.
+	output, err := r.git("cherry", targetRef, branchRef)
+	if err != nil {
+		return Unmerged, r.mergeStateErr(branch, target, err)
+	}
+	if !strings.Contains("\n"+output, "\n+") {
+		return SquashMerged, nil
+	}
+
+	// Squash merges are detected by comparing the patch-id of all of the changes on the branch
+	// since it forked from the target to the patch-ids of the commits on the target since then.
+	// The patch-ids are computed from the diffs rather than with git commit-tree and git cherry
+	// so that inspecting a repo never writes to it. Only the commits on the target that touch the
+	// files the branch changes can have the same patch-id so the others aren't read at all.
+	base, err := r.git("merge-base", targetRef, branchRef)
+	if isExitCode(err, 1) {
+		return Unmerged, nil
+	}
+	if err != nil {
+		return Unmerged, r.mergeStateErr(branch, target, err)
+	}
+	base = strings.TrimSpace(base)
+	branchDiff, err := r.git(append([]string{"diff"}, append(patchIDDiffArgs, base, branchRef)...)...)
+	if err != nil {
+		return Unmerged, r.mergeStateErr(branch, target, err)
+	}
+	if strings.TrimSpace(branchDiff) == "" {
+		return Unmerged, nil
+	}
+	paths, err := r.git(append([]string{"diff", "--name-only", "-z"}, append(patchIDDiffArgs, base, branchRef)...)...)
+	if err != nil {
+		return Unmerged, r.mergeStateErr(branch, target, err)
+	}
+	logArgs := []string{"--literal-pathspecs", "log", "--no-merges", "--patch", "--full-diff", "--format=%x00"}
+	logArgs = append(logArgs, patchIDDiffArgs...)
+	logArgs = append(logArgs, base+".."+targetRef, "--")
+	for _, path := range strings.Split(paths, "\x00") {
+		if path != "" {
+			logArgs = append(logArgs, path)
+		}
+	}
+	targetLog, err := r.git(logArgs...)
+	if err != nil {
+		return Unmerged, r.mergeStateErr(branch, target, err)
+	}
+	squashed := patchID(branchDiff)
+	for _, commitDiff := range strings.Split(targetLog, "\x00") {
+		if strings.TrimSpace(commitDiff) != "" && patchID(commitDiff) == squashed {
+			return SquashMerged, nil
+		}
+	}
+	return Unmerged, nil
+}
+
+// patchIDDiffArgs are the options that make git diff and git log produce the same diffs for the
+// same changes regardless of the user's config.
+var patchIDDiffArgs = []string{"--no-color", "--no-ext-diff", "--no-textconv", "--no-renames"}
+
+// patchID returns an identifier for the changes in diff that, like git patch-id, ignores
+// whitespace, line numbers and the hashes of the files involved.
+func patchID(diff string) string {
+	hash := sha1.New()
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "index "):
+			continue
+		case strings.HasPrefix(line, "@@"):
+			line = "@@"
+		}
+		if line = strings.Join(strings.Fields(line), ""); line != "" {
+			io.WriteString(hash, line+"\n")
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (r *repo) mergeStateErr(branch, target string, err error) error {
+	return fmt.Errorf(
+		"failed to determine whether '%s' is merged into '%s' in repo '%s': %v",
+		branch,
+		target,
+		r.Path(),
+		err)
+}
+
+// isExitCode returns a bool indicating whether err is a failed git command that exited with the
+// given code.
+func isExitCode(err error, code int) bool {
+	var cliErr *shgit.CLIError
+	return errors.As(err, &cliErr) && cliErr.ExitCode == code
+}
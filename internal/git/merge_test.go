@@ -0,0 +1,173 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ttd2089/shgit"
+)
+
+func TestMergeState(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		setup    func(g gitFunc)
+		expected MergeState
+	}{
+		{
+			name: "Returns Merged when the branch is reachable from the target",
+			setup: func(g gitFunc) {
+				g("checkout", "-q", "-b", "topic")
+				commitFile(g, "a.txt", "a\n")
+				g("update-ref", "refs/remotes/origin/main", "topic")
+			},
+			expected: Merged,
+		},
+		{
+			name: "Returns Unmerged when the branch has changes the target doesn't",
+			setup: func(g gitFunc) {
+				g("checkout", "-q", "-b", "topic")
+				commitFile(g, "a.txt", "a\n")
+			},
+			expected: Unmerged,
+		},
+		{
+			name: "Returns SquashMerged when the branch was rebased onto the target",
+			setup: func(g gitFunc) {
+				g("checkout", "-q", "-b", "topic")
+				commitFile(g, "a.txt", "a\n")
+				g("checkout", "-q", "main")
+				commitFile(g, "b.txt", "b\n")
+				g("cherry-pick", "topic")
+				g("update-ref", "refs/remotes/origin/main", "main")
+			},
+			expected: SquashMerged,
+		},
+		{
+			name: "Returns SquashMerged when the branch was squash merged into the target",
+			setup: func(g gitFunc) {
+				g("checkout", "-q", "-b", "topic")
+				commitFile(g, "a.txt", "a\n")
+				commitFile(g, "a.txt", "a\nb\n")
+				g("checkout", "-q", "main")
+				commitFile(g, "c.txt", "c\n")
+				g("merge", "-q", "--squash", "topic")
+				g("commit", "-q", "-m", "squashed")
+				g("update-ref", "refs/remotes/origin/main", "main")
+			},
+			expected: SquashMerged,
+		},
+		{
+			name: "Returns SquashMerged when the target has other changes since the squash merge",
+			setup: func(g gitFunc) {
+				g("checkout", "-q", "-b", "topic")
+				commitFile(g, "a [b].txt", "a\n")
+				g("checkout", "-q", "main")
+				g("merge", "-q", "--squash", "topic")
+				g("commit", "-q", "-m", "squashed")
+				commitFile(g, "c.txt", "c\n")
+				commitFile(g, "a [b].txt", "a\nb\n")
+				g("update-ref", "refs/remotes/origin/main", "main")
+			},
+			expected: SquashMerged,
+		},
+		{
+			name: "Returns Unmerged when a commit on the target makes the branch's changes and others",
+			setup: func(g gitFunc) {
+				g("checkout", "-q", "-b", "topic")
+				commitFile(g, "a.txt", "a\n")
+				g("checkout", "-q", "main")
+				writeFile(g, "a.txt", "a\n")
+				commitFile(g, "c.txt", "c\n")
+				g("update-ref", "refs/remotes/origin/main", "main")
+			},
+			expected: Unmerged,
+		},
+		{
+			name: "Judges the branch rather than a tag with the same name",
+			setup: func(g gitFunc) {
+				g("update-ref", "refs/remotes/origin/main", "main")
+				g("tag", "topic", "main")
+				g("checkout", "-q", "-b", "topic")
+				commitFile(g, "a.txt", "a\n")
+			},
+			expected: Unmerged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, g := newTestRepo(t)
+			tt.setup(g)
+			objects := g("count-objects")
+
+			repo, err := NewRepo(path, shgit.NewCLI())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual, err := repo.MergeState("topic", "origin/main")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("expected %v; got %v", tt.expected, actual)
+			}
+			if after := g("count-objects"); after != objects {
+				t.Errorf("expected objects to be unchanged; got '%s' before and '%s' after", objects, after)
+			}
+		})
+	}
+}
+
+// A gitFunc runs a git command in a test repo and returns its output.
+type gitFunc func(args ...string) string
+
+// newTestRepo creates a repo with one commit on main and a remote branch origin/main pointing at
+// it, and returns its path along with a gitFunc that runs commands in it.
+func newTestRepo(t *testing.T) (string, gitFunc) {
+	t.Helper()
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "ocg")
+	t.Setenv("GIT_AUTHOR_EMAIL", "ocg@localhost")
+	t.Setenv("GIT_COMMITTER_NAME", "ocg")
+	t.Setenv("GIT_COMMITTER_EMAIL", "ocg@localhost")
+
+	path := t.TempDir()
+	g := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", path}, args...)...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return string(output)
+	}
+	g("init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(path, "README"), []byte("test\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g("add", "README")
+	g("commit", "-q", "-m", "initial")
+	g("update-ref", "refs/remotes/origin/main", "main")
+	return path, g
+}
+
+// commitFile writes content to the named file in the test repo and commits it along with any other
+// staged changes.
+func commitFile(g gitFunc, name, content string) {
+	writeFile(g, name, content)
+	g("commit", "-q", "-m", "update "+name)
+}
+
+// writeFile writes content to the named file in the test repo and stages it.
+func writeFile(g gitFunc, name, content string) {
+	path := strings.TrimSpace(g("rev-parse", "--show-toplevel"))
+	if err := os.WriteFile(filepath.Join(path, name), []byte(content), 0o644); err != nil {
+		panic(err)
+	}
+	g("add", "--", name)
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// An Operation is a multi-step git operation that can be left in progress, e.g. a rebase that
+// stopped to resolve conflicts.
+type Operation string
+
+const (
+	// OperationRebase indicates that a git rebase is in progress.
+	OperationRebase Operation = "rebase"
+
+	// OperationAm indicates that a git am is in progress.
+	OperationAm Operation = "am"
+
+	// OperationMerge indicates that a git merge stopped before committing, e.g. to resolve
+	// conflicts.
+	OperationMerge Operation = "merge"
+
+	// OperationCherryPick indicates that a git cherry-pick is in progress.
+	OperationCherryPick Operation = "cherry-pick"
+
+	// OperationRevert indicates that a git revert is in progress.
+	OperationRevert Operation = "revert"
+
+	// OperationBisect indicates that a git bisect is in progress.
+	OperationBisect Operation = "bisect"
+)
+
+// operationMarkers maps the files and directories that git creates in the .git directory while an
+// operation is in progress to the Operation they indicate.
+var operationMarkers = []struct {
+	path      string
+	operation Operation
+}{
+	{"rebase-merge", OperationRebase},
+	{filepath.Join("rebase-apply", "rebasing"), OperationRebase},
+	{filepath.Join("rebase-apply", "applying"), OperationAm},
+	{"MERGE_HEAD", OperationMerge},
+	{"CHERRY_PICK_HEAD", OperationCherryPick},
+	{"REVERT_HEAD", OperationRevert},
+	{"BISECT_LOG", OperationBisect},
+}
+
+func (r *repo) Operations() ([]Operation, error) {
+	var operations []Operation
+	for _, marker := range operationMarkers {
+		_, err := os.Stat(filepath.Join(r.gitDir, marker.path))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect operations in progress in repo '%s': %v", r.Path(), err)
+		}
+		operations = append(operations, marker.operation)
+	}
+	return operations, nil
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package git
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on platforms without process groups.
+func setProcessGroup(proc *exec.Cmd) {}
+
+// killProcessGroup kills the process started by proc.
+func killProcessGroup(proc *exec.Cmd) {
+	proc.Process.Kill()
+}
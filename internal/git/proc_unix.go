@@ -0,0 +1,19 @@
+//go:build unix
+
+package git
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup configures proc to start in a new process group so that it can be killed along
+// with any processes it starts, e.g. the ssh process started by git fetch.
+func setProcessGroup(proc *exec.Cmd) {
+	proc.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills the process group started by proc.
+func killProcessGroup(proc *exec.Cmd) {
+	syscall.Kill(-proc.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"fmt"
+)
+
+func (r *repo) Remotes() ([]string, error) {
+	output, err := r.git("remote")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remotes in repo '%s': %v", r.Path(), err)
+	}
+	var remotes []string
+	for _, tokens := range tokenizeLines(output, "\t") {
+		remotes = append(remotes, tokens[0])
+	}
+	return remotes, nil
+}
+
+func (r *repo) Fetch() error {
+	_, err := r.git("fetch", "--all", "--prune", "--quiet")
+	if err != nil {
+		return fmt.Errorf("failed to fetch remotes in repo '%s': %v", r.Path(), err)
+	}
+	return nil
+}
+
+func (r *repo) FastForward(target string) error {
+	_, err := r.git("merge", "--ff-only", "--quiet", "refs/remotes/"+target)
+	if err != nil {
+		return fmt.Errorf("failed to fast-forward to '%s' in repo '%s': %v", target, r.Path(), err)
+	}
+	return nil
+}
+
+func (r *repo) UpdateBranch(branch, newSHA, oldSHA string) error {
+	_, err := r.git(
+		"update-ref",
+		"-m", fmt.Sprintf("ocg: update %s to %s", branch, newSHA),
+		"refs/heads/"+branch,
+		newSHA,
+		oldSHA)
+	if err != nil {
+		return fmt.Errorf("failed to update branch '%s' in repo '%s': %v", branch, r.Path(), err)
+	}
+	return nil
+}
+
+func (r *repo) Push(remote, branch, remoteBranch string) error {
+	_, err := r.git("push", "--quiet", remote, fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, remoteBranch))
+	if err != nil {
+		return fmt.Errorf("failed to push '%s' to '%s/%s' in repo '%s': %v", branch, remote, remoteBranch, r.Path(), err)
+	}
+	return nil
+}
@@ -5,22 +5,108 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/ttd2089/shgit"
 	"github.com/ttd2089/tyers"
 )
 
-// IsRepo returns a bool indicating whether the given path points to a git repository.
+// IsRepo returns a bool indicating whether the given path points to a git repository. The path
+// may be a working tree with a .git directory, a linked worktree or submodule with a .git file, or
+// a bare repository.
 func IsRepo(path string) (bool, error) {
-	info, err := os.Stat(filepath.Join(path, ".git"))
+	gitDir, _, err := findGitDir(path)
+	return gitDir != "", err
+}
+
+// findGitDir returns the path of the git directory for the repository at path and a bool
+// indicating whether the repository is bare. An empty path is returned if path is not a git
+// repository.
+func findGitDir(path string) (string, bool, error) {
+	dotGit := filepath.Join(path, ".git")
+	info, err := os.Stat(dotGit)
+	if err == nil && info.IsDir() {
+		return dotGit, false, nil
+	}
+	if err == nil {
+		gitDir, err := readGitFile(dotGit)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to determine if '%s' is a Git repo: %w", path, err)
+		}
+		if gitDir != "" && !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(path, gitDir)
+		}
+		return gitDir, false, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", false, fmt.Errorf("failed to determine if '%s' is a Git repo: %w", path, err)
+	}
+	isBare, err := isBareRepo(path)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to determine if '%s' is a Git repo: %w", path, err)
+	}
+	if isBare {
+		return path, true, nil
+	}
+	return "", false, nil
+}
+
+// findCommonDir returns the path of the git directory shared by all of the working trees of the
+// repository with the given git directory. The git directory of a linked worktree contains a
+// commondir file with the path of the main git directory.
+func findCommonDir(gitDir string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
 	if os.IsNotExist(err) {
-		return false, nil
+		return gitDir, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read commondir in '%s': %w", gitDir, err)
+	}
+	commonDir := filepath.FromSlash(strings.TrimSpace(string(content)))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitDir, commonDir)
 	}
+	return filepath.Clean(commonDir), nil
+}
+
+// readGitFile returns the path from a .git file, which linked worktrees and submodules use to
+// point to their git directory, or an empty string if the file is not a valid .git file.
+func readGitFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
 	if err != nil {
-		return false, fmt.Errorf("failed to determine if '%s' is a Git repo: %w", path, err)
+		return "", err
 	}
-	return info.IsDir(), nil
+	line := strings.TrimSpace(string(content))
+	if !strings.HasPrefix(line, "gitdir: ") {
+		return "", nil
+	}
+	return filepath.FromSlash(strings.TrimPrefix(line, "gitdir: ")), nil
+}
+
+// isBareRepo returns a bool indicating whether path contains the HEAD file and objects and refs
+// directories that make up a bare repository.
+func isBareRepo(path string) (bool, error) {
+	for _, entry := range []struct {
+		name  string
+		isDir bool
+	}{
+		{"HEAD", false},
+		{"objects", true},
+		{"refs", true},
+	} {
+		info, err := os.Stat(filepath.Join(path, entry.name))
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if info.IsDir() != entry.isDir {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
 // A Repo represents a Git repository on the local file system.
@@ -32,7 +118,77 @@ type Repo interface {
 	// Path returns the absolute path of the respository directory.
 	Path() string
 
+	// Bare returns a bool indicating whether the repository is a bare repository, i.e. one with
+	// no working tree.
+	Bare() bool
+
+	// CommonDir returns the path of the git directory shared by all of the repository's working
+	// trees. Repos representing different working trees of the same repository have the same
+	// CommonDir.
+	CommonDir() string
+
+	// Worktrees returns the working trees attached to the repository, including the main working
+	// tree, along with what each of them has checked out.
+	Worktrees() ([]Worktree, error)
+
+	// Submodules returns the absolute paths at which the submodules declared in the repository's
+	// .gitmodules file are checked out. The paths may not exist when a submodule hasn't been
+	// initialized. Bare repositories have no submodules.
+	Submodules() ([]string, error)
+
+	// Status returns a WorkTreeStatus describing the uncommitted changes in the repository's
+	// working tree and index.
+	Status() (WorkTreeStatus, error)
+
+	// Head returns a Head describing the branch or commit checked out in the repository.
+	Head() (Head, error)
+
+	// Operations returns the multi-step operations, e.g. rebases and merges, that are in progress
+	// in the repository.
+	Operations() ([]Operation, error)
+
+	// Stashes returns the entries in the repository's stash list, most recent first.
+	Stashes() ([]Stash, error)
+
+	// LocalBranches returns the branches in the repository's refs/heads along with the branches
+	// they track and how far ahead and behind their tracked branches they are.
 	LocalBranches() ([]LocalBranch, error)
+
+	// UnpushedCommits returns the number of commits on branch that are not reachable from any
+	// remote branch.
+	UnpushedCommits(branch string) (int, error)
+
+	// DeleteBranch deletes the named local branch regardless of whether it has been merged.
+	DeleteBranch(branch string) error
+
+	// Remotes returns the names of the repository's remotes.
+	Remotes() ([]string, error)
+
+	// Fetch fetches all of the repository's remotes, pruning remote branches that no longer exist.
+	Fetch() error
+
+	// Push pushes the local branch to remoteBranch on the named remote. An error is returned if
+	// the push is not a fast-forward.
+	Push(remote, branch, remoteBranch string) error
+
+	// FastForward fast-forwards the checked out branch to the remote branch target, e.g.
+	// origin/main, updating the working tree. An error is returned if the checked out branch
+	// can't be fast-forwarded to target.
+	FastForward(target string) error
+
+	// UpdateBranch points branch at newSHA without touching the working tree. An error is returned
+	// if branch doesn't currently point at oldSHA.
+	UpdateBranch(branch, newSHA, oldSHA string) error
+
+	// DefaultBranches returns the default branch of each remote keyed by the remote name. The
+	// default branch is resolved from refs/remotes/<remote>/HEAD, falling back to main or master
+	// when the HEAD ref is missing. Remotes with no resolvable default branch are omitted.
+	DefaultBranches() (map[string]Branch, error)
+
+	// MergeState returns a MergeState describing whether the changes on the local branch have
+	// been integrated into the remote branch target, e.g. origin/main. The repository is not
+	// modified.
+	MergeState(branch, target string) (MergeState, error)
 }
 
 // NewRepo returns a Repo representing the given path.
@@ -40,22 +196,32 @@ func NewRepo(absPath string, gitCLI shgit.CLI) (Repo, error) {
 	if !filepath.IsAbs(absPath) {
 		return nil, errors.New("NewRepo: absPath must be absolute")
 	}
-	isRepo, err := IsRepo(absPath)
+	gitDir, bare, err := findGitDir(absPath)
 	if err != nil {
 		return nil, err
 	}
-	if !isRepo {
+	if gitDir == "" {
 		return nil, tyers.Errorf(ErrNotAGitRepo, "'%s' is not a Git repo", absPath)
 	}
+	commonDir, err := findCommonDir(gitDir)
+	if err != nil {
+		return nil, err
+	}
 	return &repo{
-		path:   absPath,
-		gitCLI: gitCLI,
+		path:      absPath,
+		gitDir:    gitDir,
+		commonDir: commonDir,
+		bare:      bare,
+		gitCLI:    gitCLI,
 	}, nil
 }
 
 type repo struct {
-	path   string
-	gitCLI shgit.CLI
+	path      string
+	gitDir    string
+	commonDir string
+	bare      bool
+	gitCLI    shgit.CLI
 }
 
 func (r *repo) Name() string {
@@ -66,21 +232,29 @@ func (r *repo) Path() string {
 	return r.path
 }
 
+func (r *repo) Bare() bool {
+	return r.bare
+}
+
+func (r *repo) CommonDir() string {
+	return r.commonDir
+}
+
 func (r *repo) LocalBranches() ([]LocalBranch, error) {
-	output, err := r.gitCLI.Run(
-		"-C",
-		r.path,
+	output, err := r.git(
 		"for-each-ref",
-		"--format=%(refname) %(objectname) %(upstream:short)")
+		"--format=%(refname)%09%(objectname)%09%(upstream:short)%09%(upstream:track,nobracket)%09%(upstream:remotename)%09%(upstream:remoteref)",
+		"refs/heads",
+		"refs/remotes")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get branches in repo '%s': %v", r.Path(), err)
 	}
 
-	lines := tokenizeLines(output)
+	lines := tokenizeLines(output, "\t")
 
 	remotes := map[string]*Branch{}
 	for _, tokens := range lines {
-		if len(tokens) < 2 || len(tokens) > 3 {
+		if len(tokens) != 6 {
 			line := strings.Join(tokens, " ")
 			return nil, fmt.Errorf("repo.LocalBranches(): unexpected output from git command: %s", line)
 		}
@@ -99,9 +273,10 @@ func (r *repo) LocalBranches() ([]LocalBranch, error) {
 		if !strings.HasPrefix(tokens[0], "refs/heads/") {
 			continue
 		}
-		var tracking *Branch
-		if len(tokens) == 3 {
-			tracking, _ = remotes[tokens[2]]
+		tracking, _ := remotes[tokens[2]]
+		ahead, behind, err := parseTrack(tokens[3])
+		if err != nil {
+			return nil, fmt.Errorf("repo.LocalBranches(): %v", err)
 		}
 		name := strings.TrimPrefix(tokens[0], "refs/heads/")
 		locals = append(locals, LocalBranch{
@@ -109,22 +284,73 @@ func (r *repo) LocalBranches() ([]LocalBranch, error) {
 				Name: name,
 				SHA:  tokens[1],
 			},
-			Tracking: tracking,
+			Remote:       tokens[4],
+			RemoteBranch: strings.TrimPrefix(tokens[5], "refs/heads/"),
+			Tracking:     tracking,
+			Ahead:        ahead,
+			Behind:       behind,
+			Gone:         tokens[3] == "gone",
 		})
 	}
 
 	return locals, nil
 }
 
-func tokenizeLines(s string) [][]string {
+func (r *repo) UnpushedCommits(branch string) (int, error) {
+	output, err := r.git("rev-list", "--count", "refs/heads/"+branch, "--not", "--remotes")
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unpushed commits on '%s' in repo '%s': %v", branch, r.Path(), err)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil {
+		return 0, fmt.Errorf("repo.UnpushedCommits(): unexpected output from git command: %s", output)
+	}
+	return count, nil
+}
+
+func (r *repo) DeleteBranch(branch string) error {
+	_, err := r.git("branch", "--delete", "--force", branch)
+	if err != nil {
+		return fmt.Errorf("failed to delete branch '%s' in repo '%s': %v", branch, r.Path(), err)
+	}
+	return nil
+}
+
+// git runs a git command in the repository directory.
+func (r *repo) git(args ...string) (string, error) {
+	return r.gitCLI.Run(append([]string{"-C", r.path}, args...)...)
+}
+
+// parseTrack parses the ahead and behind counts from the output of the for-each-ref field
+// %(upstream:track,nobracket), e.g. "ahead 1, behind 2".
+func parseTrack(track string) (ahead int, behind int, err error) {
+	if track == "" || track == "gone" {
+		return 0, 0, nil
+	}
+	for _, part := range strings.Split(track, ", ") {
+		var n int
+		if _, err = fmt.Sscanf(part, "ahead %d", &n); err == nil {
+			ahead = n
+			continue
+		}
+		if _, err = fmt.Sscanf(part, "behind %d", &n); err == nil {
+			behind = n
+			continue
+		}
+		return 0, 0, fmt.Errorf("unexpected upstream tracking info: %s", track)
+	}
+	return ahead, behind, nil
+}
+
+func tokenizeLines(s string, sep string) [][]string {
 	lines := strings.Split(s, "\n")
 	tokens := make([][]string, 0, len(lines))
 	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
 			continue
 		}
-		tokens = append(tokens, strings.Split(line, " "))
+		tokens = append(tokens, strings.Split(line, sep))
 	}
 	return tokens
 }
@@ -0,0 +1,74 @@
+package git
+
+import (
+	"testing"
+)
+
+func TestParseTrack(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		track          string
+		expectedAhead  int
+		expectedBehind int
+		expectErr      bool
+	}{
+		{
+			name:  "Parses empty track as in sync",
+			track: "",
+		},
+		{
+			name:  "Parses gone as in sync",
+			track: "gone",
+		},
+		{
+			name:          "Parses ahead",
+			track:         "ahead 3",
+			expectedAhead: 3,
+		},
+		{
+			name:           "Parses behind",
+			track:          "behind 2",
+			expectedBehind: 2,
+		},
+		{
+			name:           "Parses ahead and behind",
+			track:          "ahead 1, behind 12",
+			expectedAhead:  1,
+			expectedBehind: 12,
+		},
+		{
+			name:      "Returns error for unknown parts",
+			track:     "ahead 1, sideways 2",
+			expectErr: true,
+		},
+		{
+			name:      "Returns error for missing counts",
+			track:     "ahead",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ahead, behind, err := parseTrack(tt.track)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected error; got ahead %d, behind %d", ahead, behind)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ahead != tt.expectedAhead || behind != tt.expectedBehind {
+				t.Errorf(
+					"expected ahead %d, behind %d; got ahead %d, behind %d",
+					tt.expectedAhead,
+					tt.expectedBehind,
+					ahead,
+					behind)
+			}
+		})
+	}
+}
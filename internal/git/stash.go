@@ -0,0 +1,80 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Stash represents an entry in a repository's stash list.
+type Stash struct {
+
+	// Index is the position of the Stash in the stash list, i.e. the N in stash@{N}.
+	Index int
+
+	// Branch is the name of the branch that was checked out when the Stash was created, or
+	// "(no branch)" if HEAD was detached.
+	Branch string
+
+	// Message is the message describing the Stash.
+	Message string
+
+	// Created is the time the Stash was created.
+	Created time.Time
+}
+
+// Age returns the time elapsed since the Stash was created.
+func (s Stash) Age() time.Duration {
+	return time.Since(s.Created)
+}
+
+func (r *repo) Stashes() ([]Stash, error) {
+	// Stashes are created from a working tree so a bare repository has none.
+	if r.bare {
+		return nil, nil
+	}
+	output, err := r.git("stash", "list", "--format=%gd%x09%ct%x09%gs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stashes in repo '%s': %v", r.Path(), err)
+	}
+	lines := tokenizeLines(output, "\t")
+	stashes := make([]Stash, 0, len(lines))
+	for _, tokens := range lines {
+		stash, err := parseStash(tokens)
+		if err != nil {
+			return nil, fmt.Errorf("repo.Stashes(): %v", err)
+		}
+		stashes = append(stashes, stash)
+	}
+	return stashes, nil
+}
+
+// parseStash parses a Stash from the tokens of a line of git stash list output formatted using
+// %gd%x09%ct%x09%gs, e.g. "stash@{0}	1670000000	WIP on main: 1a2b3c4 commit subject".
+func parseStash(tokens []string) (Stash, error) {
+	line := strings.Join(tokens, "\t")
+	if len(tokens) < 3 {
+		return Stash{}, fmt.Errorf("unexpected output from git command: %s", line)
+	}
+	var stash Stash
+	if _, err := fmt.Sscanf(tokens[0], "stash@{%d}", &stash.Index); err != nil {
+		return Stash{}, fmt.Errorf("unexpected output from git command: %s", line)
+	}
+	created, err := strconv.ParseInt(tokens[1], 10, 64)
+	if err != nil {
+		return Stash{}, fmt.Errorf("unexpected output from git command: %s", line)
+	}
+	stash.Created = time.Unix(created, 0)
+
+	// The reflog subject is "WIP on <branch>: <sha> <subject>" for stashes created without a
+	// message and "On <branch>: <message>" otherwise.
+	subject := strings.Join(tokens[2:], "\t")
+	if s := strings.TrimPrefix(subject, "WIP on "); s != subject {
+		subject = s
+	} else {
+		subject = strings.TrimPrefix(subject, "On ")
+	}
+	stash.Branch, stash.Message, _ = strings.Cut(subject, ": ")
+	return stash, nil
+}
@@ -0,0 +1,90 @@
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseStash(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		tokens        []string
+		expectedStash Stash
+		expectErr     bool
+	}{
+		{
+			name:   "Parses stash created without a message",
+			tokens: []string{"stash@{0}", "1670000000", "WIP on main: 1a2b3c4 commit subject"},
+			expectedStash: Stash{
+				Index:   0,
+				Branch:  "main",
+				Message: "1a2b3c4 commit subject",
+				Created: time.Unix(1670000000, 0),
+			},
+		},
+		{
+			name:   "Parses stash created with a message",
+			tokens: []string{"stash@{2}", "1670000000", "On feature/x: half done"},
+			expectedStash: Stash{
+				Index:   2,
+				Branch:  "feature/x",
+				Message: "half done",
+				Created: time.Unix(1670000000, 0),
+			},
+		},
+		{
+			name:   "Parses stash created on a detached HEAD",
+			tokens: []string{"stash@{1}", "1670000000", "WIP on (no branch): 1a2b3c4 commit subject"},
+			expectedStash: Stash{
+				Index:   1,
+				Branch:  "(no branch)",
+				Message: "1a2b3c4 commit subject",
+				Created: time.Unix(1670000000, 0),
+			},
+		},
+		{
+			name:   "Keeps colons and tabs in the message",
+			tokens: []string{"stash@{0}", "1670000000", "On main: fix: this", "and that"},
+			expectedStash: Stash{
+				Index:   0,
+				Branch:  "main",
+				Message: "fix: this\tand that",
+				Created: time.Unix(1670000000, 0),
+			},
+		},
+		{
+			name:      "Returns error for missing tokens",
+			tokens:    []string{"stash@{0}", "1670000000"},
+			expectErr: true,
+		},
+		{
+			name:      "Returns error for invalid selector",
+			tokens:    []string{"refs/stash", "1670000000", "On main: message"},
+			expectErr: true,
+		},
+		{
+			name:      "Returns error for invalid timestamp",
+			tokens:    []string{"stash@{0}", "yesterday", "On main: message"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stash, err := parseStash(tt.tokens)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected error; got %+v", stash)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stash != tt.expectedStash {
+				t.Errorf("expected %+v; got %+v", tt.expectedStash, stash)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A WorkTreeStatus describes the uncommitted changes in a repository's working tree and index.
+type WorkTreeStatus struct {
+
+	// Staged contains the paths of files with changes in the index.
+	Staged []string
+
+	// Modified contains the paths of tracked files with changes in the working tree that are not
+	// in the index.
+	Modified []string
+
+	// Untracked contains the paths of files that are not tracked and not ignored.
+	Untracked []string
+
+	// Conflicted contains the paths of files with unresolved merge conflicts.
+	Conflicted []string
+}
+
+// Clean returns a bool indicating whether the WorkTreeStatus contains no changes.
+func (s WorkTreeStatus) Clean() bool {
+	return len(s.Staged) == 0 &&
+		len(s.Modified) == 0 &&
+		len(s.Untracked) == 0 &&
+		len(s.Conflicted) == 0
+}
+
+func (r *repo) Status() (WorkTreeStatus, error) {
+	output, err := r.git("status", "--porcelain=v2", "-z")
+	if err != nil {
+		return WorkTreeStatus{}, fmt.Errorf("failed to get status of repo '%s': %v", r.Path(), err)
+	}
+	status, err := parseStatus(output)
+	if err != nil {
+		return WorkTreeStatus{}, fmt.Errorf("repo.Status(): %v", err)
+	}
+	return status, nil
+}
+
+// parseStatus parses the output of git status --porcelain=v2 -z.
+//
+// https://git-scm.com/docs/git-status#_porcelain_format_version_2
+func parseStatus(output string) (WorkTreeStatus, error) {
+	status := WorkTreeStatus{}
+	records := strings.Split(output, "\x00")
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if record == "" {
+			continue
+		}
+		var fields []string
+		switch record[0] {
+		case '1':
+			fields = strings.SplitN(record, " ", 9)
+		case '2':
+			fields = strings.SplitN(record, " ", 10)
+			// The original path of a renamed or copied file is the next record.
+			i++
+		case 'u':
+			fields = strings.SplitN(record, " ", 11)
+			if len(fields) == 11 {
+				status.Conflicted = append(status.Conflicted, fields[10])
+			}
+			continue
+		case '?':
+			status.Untracked = append(status.Untracked, strings.TrimPrefix(record, "? "))
+			continue
+		case '!', '#':
+			continue
+		}
+		if len(fields) < 9 || len(fields[1]) != 2 {
+			return WorkTreeStatus{}, fmt.Errorf("unexpected output from git command: %s", record)
+		}
+		path := fields[len(fields)-1]
+		if fields[1][0] != '.' {
+			status.Staged = append(status.Staged, path)
+		}
+		if fields[1][1] != '.' {
+			status.Modified = append(status.Modified, path)
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,125 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+
+	// records joins the records of git status --porcelain=v2 -z output.
+	records := func(records ...string) string {
+		return strings.Join(records, "\x00") + "\x00"
+	}
+
+	tests := []struct {
+		name           string
+		output         string
+		expectedStatus WorkTreeStatus
+		expectErr      bool
+	}{
+		{
+			name:           "Parses empty output",
+			output:         "",
+			expectedStatus: WorkTreeStatus{},
+		},
+		{
+			name: "Ignores headers and ignored files",
+			output: records(
+				"# branch.oid 1a2b3c4d5e6f7a8b9c0d1a2b3c4d5e6f7a8b9c0d",
+				"# branch.head main",
+				"! build/"),
+			expectedStatus: WorkTreeStatus{},
+		},
+		{
+			name: "Parses staged and modified changes",
+			output: records(
+				"1 M. N... 100644 100644 100644 1a2b3c4 5d6e7f8 staged.txt",
+				"1 .M N... 100644 100644 100644 1a2b3c4 1a2b3c4 modified.txt",
+				"1 MM N... 100644 100644 100644 1a2b3c4 5d6e7f8 both.txt"),
+			expectedStatus: WorkTreeStatus{
+				Staged:   []string{"staged.txt", "both.txt"},
+				Modified: []string{"modified.txt", "both.txt"},
+			},
+		},
+		{
+			name: "Parses renames without treating the original path as a record",
+			output: records(
+				"2 R. N... 100644 100644 100644 1a2b3c4 1a2b3c4 R100 new.txt",
+				"old.txt",
+				"1 .M N... 100644 100644 100644 1a2b3c4 1a2b3c4 other.txt"),
+			expectedStatus: WorkTreeStatus{
+				Staged:   []string{"new.txt"},
+				Modified: []string{"other.txt"},
+			},
+		},
+		{
+			name: "Parses renames whose original path looks like a record",
+			output: records(
+				"2 RM N... 100644 100644 100644 1a2b3c4 1a2b3c4 R100 new.txt",
+				"? old.txt"),
+			expectedStatus: WorkTreeStatus{
+				Staged:   []string{"new.txt"},
+				Modified: []string{"new.txt"},
+			},
+		},
+		{
+			name: "Parses unmerged files as conflicted",
+			output: records(
+				"u UU N... 100644 100644 100644 100644 1a2b3c4 5d6e7f8 9a0b1c2 conflict.txt"),
+			expectedStatus: WorkTreeStatus{
+				Conflicted: []string{"conflict.txt"},
+			},
+		},
+		{
+			name:   "Parses untracked files",
+			output: records("? new.txt", "? dir/"),
+			expectedStatus: WorkTreeStatus{
+				Untracked: []string{"new.txt", "dir/"},
+			},
+		},
+		{
+			name: "Parses paths with spaces",
+			output: records(
+				"1 M. N... 100644 100644 100644 1a2b3c4 5d6e7f8 a file.txt",
+				"2 R. N... 100644 100644 100644 1a2b3c4 1a2b3c4 R100 a new file.txt",
+				"an old file.txt",
+				"u UU N... 100644 100644 100644 100644 1a2b3c4 5d6e7f8 9a0b1c2 a conflict.txt",
+				"? an untracked file.txt"),
+			expectedStatus: WorkTreeStatus{
+				Staged:     []string{"a file.txt", "a new file.txt"},
+				Conflicted: []string{"a conflict.txt"},
+				Untracked:  []string{"an untracked file.txt"},
+			},
+		},
+		{
+			name:      "Returns error for truncated records",
+			output:    records("1 M. N..."),
+			expectErr: true,
+		},
+		{
+			name:      "Returns error for unknown records",
+			output:    records("x unknown"),
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := parseStatus(tt.output)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected error; got %+v", status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(status, tt.expectedStatus) {
+				t.Errorf("expected %+v; got %+v", tt.expectedStatus, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+func (r *repo) Submodules() ([]string, error) {
+	if r.bare {
+		return nil, nil
+	}
+	output, err := r.git(
+		"config",
+		"--file", filepath.Join(r.path, ".gitmodules"),
+		"--get-regexp", `^submodule\..*\.path$`)
+	if isExitCode(err, 1) {
+		// git config exits with 1 when the file doesn't exist or nothing matches.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submodules in repo '%s': %v", r.Path(), err)
+	}
+	var paths []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		_, path, found := strings.Cut(line, " ")
+		if !found {
+			return nil, fmt.Errorf("repo.Submodules(): unexpected output from git command: %s", line)
+		}
+		paths = append(paths, filepath.Join(r.path, filepath.FromSlash(path)))
+	}
+	return paths, nil
+}
@@ -0,0 +1,61 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// A Worktree represents a working tree attached to a repository.
+type Worktree struct {
+
+	// Path is the absolute path of the working tree.
+	Path string
+
+	// Branch is the name of the branch checked out in the working tree. Branch is empty when HEAD
+	// is detached or the Worktree is bare.
+	Branch string
+
+	// SHA is the hash of the commit checked out in the working tree.
+	SHA string
+
+	// Detached indicates whether HEAD is detached in the working tree.
+	Detached bool
+
+	// Bare indicates whether the Worktree represents a bare repository.
+	Bare bool
+}
+
+func (r *repo) Worktrees() ([]Worktree, error) {
+	output, err := r.git("worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktrees in repo '%s': %v", r.Path(), err)
+	}
+
+	// The porcelain format has one line per attribute with a blank line between worktrees.
+	// https://git-scm.com/docs/git-worktree#_porcelain_format
+	var worktrees []Worktree
+	for _, block := range strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n\n") {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+		var worktree Worktree
+		for _, line := range strings.Split(block, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				worktree.Path = filepath.FromSlash(value)
+			case "HEAD":
+				worktree.SHA = value
+			case "branch":
+				worktree.Branch = strings.TrimPrefix(value, "refs/heads/")
+			case "detached":
+				worktree.Detached = true
+			case "bare":
+				worktree.Bare = true
+			}
+		}
+		worktrees = append(worktrees, worktree)
+	}
+	return worktrees, nil
+}
@@ -0,0 +1,205 @@
+package ignore
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// FileName is the name of the files holding rules for the directories that ocg won't search for
+// repositories.
+const FileName = ".ocgignore"
+
+// MatchGlob returns a bool indicating whether p matches the glob pattern. Patterns containing a
+// path separator are matched against the whole path and other patterns are matched against the
+// base name of p.
+func MatchGlob(pattern, p string) bool {
+	if !strings.ContainsRune(pattern, filepath.Separator) {
+		p = filepath.Base(p)
+	}
+	matched, _ := filepath.Match(pattern, p)
+	return matched
+}
+
+// A pattern is a single rule from an ignore file.
+type pattern struct {
+
+	// segments are the slash separated parts of the pattern. A ** segment matches any number of
+	// path segments.
+	segments []string
+
+	// negate indicates whether paths matching the pattern are included rather than ignored.
+	negate bool
+
+	// dirOnly indicates whether the pattern only matches directories.
+	dirOnly bool
+
+	// anchored indicates whether the pattern is matched against the path relative to the directory
+	// containing the ignore file rather than against the base name.
+	anchored bool
+}
+
+// Rules holds the patterns from an ignore file. The rules apply to the descendants of the
+// directory containing the file.
+type Rules struct {
+	dir      string
+	patterns []pattern
+}
+
+// Parse parses the content of an ignore file in dir. The syntax follows gitignore: blank lines and
+// lines starting with # are skipped, a leading ! re-includes paths excluded by earlier patterns, a
+// trailing / only matches directories, patterns containing a / are matched relative to dir, and
+// ** matches any number of directories.
+func Parse(dir, content string) (Rules, error) {
+	rules := Rules{dir: dir}
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(strings.TrimSuffix(line, "\r"), " ")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var p pattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			p.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		p.segments = strings.Split(line, "/")
+		for _, segment := range p.segments {
+			if _, err := path.Match(segment, ""); err != nil {
+				return Rules{}, fmt.Errorf("invalid pattern on line %d: %w", i+1, err)
+			}
+		}
+		rules.patterns = append(rules.patterns, p)
+	}
+	return rules, nil
+}
+
+// Load returns the rules from the ignore file in dir, or empty rules if there is no ignore file.
+func Load(dir string) (Rules, error) {
+	content, err := os.ReadFile(filepath.Join(dir, FileName))
+	if os.IsNotExist(err) {
+		return Rules{dir: dir}, nil
+	}
+	if err != nil {
+		return Rules{}, fmt.Errorf("failed to read '%s': %w", filepath.Join(dir, FileName), err)
+	}
+	rules, err := Parse(dir, string(content))
+	if err != nil {
+		return Rules{}, fmt.Errorf("failed to parse '%s': %w", filepath.Join(dir, FileName), err)
+	}
+	return rules, nil
+}
+
+// Match returns a bool indicating whether the rules ignore the given path and a bool indicating
+// whether any of the rules matched it. When several patterns match, the last one wins.
+func (r Rules) Match(p string, isDir bool) (ignored bool, matched bool) {
+	rel, err := filepath.Rel(r.dir, p)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false, false
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i := len(r.patterns) - 1; i >= 0; i-- {
+		pattern := r.patterns[i]
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+		if pattern.matches(segments) {
+			return !pattern.negate, true
+		}
+	}
+	return false, false
+}
+
+func (p pattern) matches(segments []string) bool {
+	if !p.anchored {
+		return matchSegments(p.segments, segments[len(segments)-1:])
+	}
+	return matchSegments(p.segments, segments)
+}
+
+// matchSegments returns a bool indicating whether the pattern segments match the path segments.
+func matchSegments(patterns, segments []string) bool {
+	if len(patterns) == 0 {
+		return len(segments) == 0
+	}
+	if patterns[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(patterns[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	matched, _ := path.Match(patterns[0], segments[0])
+	return matched && matchSegments(patterns[1:], segments[1:])
+}
+
+// A Matcher applies the ignore files found in a directory tree. Rules from ignore files deeper in
+// the tree take precedence over rules from their ancestors.
+type Matcher struct {
+	root  string
+	rules map[string]Rules
+}
+
+// NewMatcher returns a Matcher for the tree rooted at root.
+func NewMatcher(root string) *Matcher {
+	return &Matcher{
+		root:  filepath.Clean(root),
+		rules: map[string]Rules{},
+	}
+}
+
+// Ignored returns a bool indicating whether p is ignored by the ignore files in the root of the
+// tree or any of the directories between the root and p. Paths outside the tree are never ignored.
+func (m *Matcher) Ignored(p string, isDir bool) (bool, error) {
+	var dirs []string
+	for dir := filepath.Dir(filepath.Clean(p)); ; dir = filepath.Dir(dir) {
+		dirs = append(dirs, dir)
+		if dir == m.root {
+			break
+		}
+		if dir == filepath.Dir(dir) {
+			return false, nil
+		}
+	}
+	ignored := false
+	for i := len(dirs) - 1; i >= 0; i-- {
+		rules, err := m.load(dirs[i])
+		if err != nil {
+			return false, err
+		}
+		if dirIgnored, matched := rules.Match(p, isDir); matched {
+			ignored = dirIgnored
+		}
+	}
+	return ignored, nil
+}
+
+func (m *Matcher) load(dir string) (Rules, error) {
+	if rules, ok := m.rules[dir]; ok {
+		return rules, nil
+	}
+	rules, err := Load(dir)
+	if err != nil {
+		return Rules{}, err
+	}
+	m.rules[dir] = rules
+	return rules, nil
+}
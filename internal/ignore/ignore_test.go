@@ -0,0 +1,150 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"node_modules", "/src/app/node_modules", true},
+		{"node_*", "/src/app/node_modules", true},
+		{"node_modules", "/src/node_modules/app", false},
+		{"/src/*", "/src/app", true},
+		{"/src/*", "/src/app/vendor", false},
+		{"/src/*/vendor", "/src/app/vendor", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			pattern := filepath.FromSlash(tt.pattern)
+			path := filepath.FromSlash(tt.path)
+			if actual := MatchGlob(pattern, path); actual != tt.expected {
+				t.Fatalf("expected %v; got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+
+	rules, err := Parse("/src", `
+# Comments and blank lines are skipped
+
+node_modules
+build/
+/vendor
+docs/**/generated
+**/cache
+tmp*
+!tmp-keep
+\!bang
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		path            string
+		isDir           bool
+		expectedIgnored bool
+		expectedMatched bool
+	}{
+		{"/src/node_modules", true, true, true},
+		{"/src/app/node_modules", true, true, true},
+		{"/src/build", true, true, true},
+		{"/src/build", false, false, false},
+		{"/src/vendor", true, true, true},
+		{"/src/app/vendor", true, false, false},
+		{"/src/docs/generated", true, true, true},
+		{"/src/docs/api/v1/generated", true, true, true},
+		{"/src/cache", true, true, true},
+		{"/src/app/cache", true, true, true},
+		{"/src/tmp", true, true, true},
+		{"/src/tmp-keep", true, false, true},
+		{"/src/!bang", true, true, true},
+		{"/src/app", true, false, false},
+		{"/src", true, false, false},
+		{"/other/node_modules", true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			ignored, matched := rules.Match(filepath.FromSlash(tt.path), tt.isDir)
+			if ignored != tt.expectedIgnored || matched != tt.expectedMatched {
+				t.Fatalf(
+					"expected ignored=%v matched=%v; got ignored=%v matched=%v",
+					tt.expectedIgnored,
+					tt.expectedMatched,
+					ignored,
+					matched)
+			}
+		})
+	}
+}
+
+func TestParseReturnsErrorForInvalidPattern(t *testing.T) {
+	if _, err := Parse("/src", "ok\n[\n"); err == nil {
+		t.Fatalf("expected error; got nil")
+	}
+}
+
+func TestMatcher(t *testing.T) {
+
+	root := t.TempDir()
+	writeFile := func(rel, content string) {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(FileName, "vendor\nscratch*\n")
+	writeFile("app/"+FileName, "!vendor\nbuild\n")
+
+	matcher := NewMatcher(root)
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"vendor", true},
+		{"lib/vendor", true},
+		{"app/vendor", false},
+		{"app/build", true},
+		{"build", false},
+		{"scratch-1", true},
+		{"app/scratch-1", true},
+		{"app", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			ignored, err := matcher.Ignored(filepath.Join(root, filepath.FromSlash(tt.path)), true)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ignored != tt.expected {
+				t.Fatalf("expected %v; got %v", tt.expected, ignored)
+			}
+		})
+	}
+
+	t.Run("Never ignores paths outside the tree", func(t *testing.T) {
+		ignored, err := matcher.Ignored(filepath.Join(filepath.Dir(root), "vendor"), true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ignored {
+			t.Fatalf("expected false; got true")
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package opts
+
+import (
+	"fmt"
+)
+
+// A Source identifies where the value of an option came from.
+type Source int
+
+const (
+
+	// SourceDefault indicates that an option has its default value.
+	SourceDefault Source = iota
+
+	// SourceConfig indicates that the value of an option came from a config file.
+	SourceConfig
+
+	// SourceEnv indicates that the value of an option came from an environment variable.
+	SourceEnv
+
+	// SourceCLI indicates that the value of an option came from the command line.
+	SourceCLI
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceConfig:
+		return "config"
+	case SourceEnv:
+		return "env"
+	case SourceCLI:
+		return "command line"
+	default:
+		return "default"
+	}
+}
+
+// A LookupFunc returns the value for key and a bool indicating whether it's set.
+type LookupFunc func(key string) (string, bool)
+
+// A BoundOption binds an option to an environment variable and a config key that provide its value
+// when it isn't given on the command line. The command line takes precedence over the environment
+// variable, which takes precedence over the config key, which takes precedence over the default
+// value of the option.
+type BoundOption struct {
+
+	// The bound option. It must have a long name.
+	NamedOption
+
+	// The name of the environment variable bound to the option, or empty if there is none.
+	Env string
+
+	// The config key bound to the option, or empty if there is none.
+	ConfigKey string
+
+	// Where the value of the option came from.
+	Source Source
+}
+
+// Bind returns a BoundOption that binds option to the environment variable env and the config key
+// configKey. Either may be empty.
+func Bind(option NamedOption, env, configKey string) *BoundOption {
+	return &BoundOption{
+		NamedOption: option,
+		Env:         env,
+		ConfigKey:   configKey,
+	}
+}
+
+func (b *BoundOption) Parse(args []string) (bool, []string, error) {
+	parsed, remaining, err := b.NamedOption.Parse(args)
+	if parsed {
+		b.Source = SourceCLI
+	}
+	return parsed, remaining, err
+}
+
+// Resolve sets the value of the option from its environment variable using env, or failing that
+// from its config key using config, unless the value was given on the command line. Values are
+// parsed exactly like values given on the command line and empty values are ignored.
+func (b *BoundOption) Resolve(env, config LookupFunc) error {
+	if b.Source == SourceCLI {
+		return nil
+	}
+	if value, ok := lookup(env, b.Env); ok {
+		b.Source = SourceEnv
+		return b.Annotate(b.set(value))
+	}
+	if value, ok := lookup(config, b.ConfigKey); ok {
+		b.Source = SourceConfig
+		return b.Annotate(b.set(value))
+	}
+	return nil
+}
+
+// Annotate returns err prefixed with the environment variable or config key that the value of the
+// option came from so errors about the value can be traced back to it. Errors are returned as-is
+// when the value didn't come from either, and nil is returned when err is nil.
+func (b *BoundOption) Annotate(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case b.Source == SourceEnv:
+		return fmt.Errorf("environment variable %s: %w", b.Env, err)
+	case b.Source == SourceConfig:
+		return fmt.Errorf("config key %s: %w", b.ConfigKey, err)
+	default:
+		return err
+	}
+}
+
+func lookup(fn LookupFunc, key string) (string, bool) {
+	if fn == nil || key == "" {
+		return "", false
+	}
+	value, ok := fn(key)
+	return value, ok && value != ""
+}
+
+func (b *BoundOption) set(value string) error {
+	_, _, err := b.NamedOption.Parse([]string{fmt.Sprintf("--%s=%s", b.Name().LongName, value)})
+	return err
+}
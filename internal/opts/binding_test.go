@@ -0,0 +1,96 @@
+package opts
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBoundOption(t *testing.T) {
+
+	lookupFrom := func(values map[string]string) LookupFunc {
+		return func(key string) (string, bool) {
+			value, ok := values[key]
+			return value, ok
+		}
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		env            map[string]string
+		config         map[string]string
+		expectedValue  int
+		expectedSource Source
+	}{
+		{
+			name:           "Keeps default when nothing is set",
+			expectedValue:  1,
+			expectedSource: SourceDefault,
+		},
+		{
+			name:           "Uses config over default",
+			config:         map[string]string{"jobs": "2"},
+			expectedValue:  2,
+			expectedSource: SourceConfig,
+		},
+		{
+			name:           "Uses env over config",
+			env:            map[string]string{"OCG_JOBS": "3"},
+			config:         map[string]string{"jobs": "2"},
+			expectedValue:  3,
+			expectedSource: SourceEnv,
+		},
+		{
+			name:           "Uses command line over env",
+			args:           []string{"-j", "4"},
+			env:            map[string]string{"OCG_JOBS": "3"},
+			config:         map[string]string{"jobs": "2"},
+			expectedValue:  4,
+			expectedSource: SourceCLI,
+		},
+		{
+			name:           "Ignores empty env",
+			env:            map[string]string{"OCG_JOBS": ""},
+			config:         map[string]string{"jobs": "2"},
+			expectedValue:  2,
+			expectedSource: SourceConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			underTest := Bind(&IntOpt{OptionName: OptionName{LongName: "jobs", ShortName: 'j'}, Value: 1}, "OCG_JOBS", "jobs")
+			if _, err := Parse(tt.args, []Option{underTest}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := underTest.Resolve(lookupFrom(tt.env), lookupFrom(tt.config)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual := underTest.NamedOption.(*IntOpt).Value; actual != tt.expectedValue {
+				t.Errorf("expected value %d; got %d", tt.expectedValue, actual)
+			}
+			if underTest.Source != tt.expectedSource {
+				t.Errorf("expected source %v; got %v", tt.expectedSource, underTest.Source)
+			}
+		})
+	}
+
+	t.Run("Returns invalid values from env", func(t *testing.T) {
+		underTest := Bind(&IntOpt{OptionName: OptionName{LongName: "jobs"}}, "OCG_JOBS", "jobs")
+		err := underTest.Resolve(lookupFrom(map[string]string{"OCG_JOBS": "many"}), nil)
+		if !errors.Is(err, ErrInvalidOptionValue) {
+			t.Fatalf("expected '%v'; got '%v'", ErrInvalidOptionValue, err)
+		}
+	})
+
+	t.Run("Sets flags from env", func(t *testing.T) {
+		flag := &FlagOpt{OptionName: OptionName{LongName: "follow-symlinks"}}
+		underTest := Bind(flag, "OCG_FOLLOW_SYMLINKS", "")
+		if err := underTest.Resolve(lookupFrom(map[string]string{"OCG_FOLLOW_SYMLINKS": "true"}), nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !flag.Value {
+			t.Fatalf("expected true; got false")
+		}
+	})
+}
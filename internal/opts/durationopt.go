@@ -0,0 +1,28 @@
+package opts
+
+import (
+	"time"
+)
+
+// A DurationOpt represents an option that contains a time.Duration value.
+type DurationOpt struct {
+
+	// The name(s) of the option.
+	OptionName
+
+	// The value of the option.
+	Value time.Duration
+}
+
+func (d *DurationOpt) Parse(args []string) (bool, []string, error) {
+	parsed, value, remaining, err := parseValue(d.OptionName, args)
+	if err != nil || !parsed {
+		return false, remaining, err
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return false, nil, NewInvalidOptionValueHelpText(d.LongName, value, "must be a duration, e.g. 30s or 2m")
+	}
+	d.Value = duration
+	return true, remaining, nil
+}
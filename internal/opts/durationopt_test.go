@@ -0,0 +1,120 @@
+package opts
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDurationOpt(t *testing.T) {
+
+	underTest := DurationOpt{
+		OptionName: OptionName{
+			ShortName: 't',
+			LongName:  "timeout",
+		},
+	}
+
+	type result struct {
+		parsed    bool
+		remaining []string
+		err       error
+	}
+
+	tests := []struct {
+		name           string
+		input          []string
+		expectedResult result
+		expectedValue  time.Duration
+	}{
+		{
+			name:  "Parses short name reference with separate value",
+			input: []string{"-t", "30s"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: 30 * time.Second,
+		},
+		{
+			name:  "Parses short name reference with attached value",
+			input: []string{"-t2m"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: 2 * time.Minute,
+		},
+		{
+			name:  "Parses long name reference with equals",
+			input: []string{"--timeout=1m30s"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: 90 * time.Second,
+		},
+		{
+			name:  "Ignores different long name reference",
+			input: []string{"--timeouts=1s"},
+			expectedResult: result{
+				parsed:    false,
+				remaining: []string{"--timeouts=1s"},
+			},
+		},
+		{
+			name:  "Returns error for value without unit",
+			input: []string{"--timeout=30"},
+			expectedResult: result{
+				err: ErrInvalidOptionValue,
+			},
+		},
+		{
+			name:  "Returns error for missing value",
+			input: []string{"-t"},
+			expectedResult: result{
+				err: ErrMissingOptionValue,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+			underTest.Value = 0
+
+			parsed, remaining, err := underTest.Parse(tt.input)
+
+			if tt.expectedResult.err != nil {
+				if !errors.Is(err, tt.expectedResult.err) {
+					t.Errorf("expected err='%v'; got '%v'", tt.expectedResult.err, err)
+					t.FailNow()
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if parsed != tt.expectedResult.parsed {
+				t.Errorf("expected parsed='%t'; got '%t'", tt.expectedResult.parsed, parsed)
+			}
+
+			if len(remaining) != len(tt.expectedResult.remaining) {
+				t.Errorf("expected '%+v'; got '%v'", tt.expectedResult.remaining, remaining)
+				t.FailNow()
+			}
+			for i := range tt.expectedResult.remaining {
+				if remaining[i] != tt.expectedResult.remaining[i] {
+					t.Errorf("expected '%+v'; got '%v'", tt.expectedResult.remaining, remaining)
+					t.FailNow()
+				}
+			}
+
+			if underTest.Value != tt.expectedValue {
+				t.Errorf("expected value='%s'; got '%s'", tt.expectedValue, underTest.Value)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package opts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// An EnumOpt represents an option that contains one of a fixed set of string values.
+type EnumOpt struct {
+
+	// The name(s) of the option.
+	OptionName
+
+	// The values the option accepts.
+	Values []string
+
+	// The value of the option.
+	Value string
+}
+
+func (e *EnumOpt) Parse(args []string) (bool, []string, error) {
+	parsed, value, remaining, err := parseValue(e.OptionName, args)
+	if err != nil || !parsed {
+		return false, remaining, err
+	}
+	if !e.accepts(value) {
+		helpText := fmt.Sprintf("must be one of %s", strings.Join(e.Values, ", "))
+		return false, nil, NewInvalidOptionValueHelpText(e.LongName, value, helpText)
+	}
+	e.Value = value
+	return true, remaining, nil
+}
+
+func (e *EnumOpt) accepts(value string) bool {
+	for _, v := range e.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
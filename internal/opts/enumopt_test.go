@@ -0,0 +1,176 @@
+package opts
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEnumOpt(t *testing.T) {
+
+	underTest := EnumOpt{
+		OptionName: OptionName{
+			ShortName: 'f',
+			LongName:  "format",
+		},
+		Values: []string{"yaml", "json"},
+	}
+
+	type result struct {
+		parsed    bool
+		remaining []string
+		err       error
+	}
+
+	tests := []struct {
+		name           string
+		input          []string
+		expectedResult result
+		expectedValue  string
+	}{
+		{
+			name:  "Parses short name reference with separate value",
+			input: []string{"-f", "json"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: "json",
+		},
+		{
+			name:  "Parses short name reference with attached value",
+			input: []string{"-fjson"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: "json",
+		},
+		{
+			name:  "Parses long name reference with separate value",
+			input: []string{"--format", "json"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: "json",
+		},
+		{
+			name:  "Parses long name reference with equals",
+			input: []string{"--format=json"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: "json",
+		},
+		{
+			name:  "Parses other accepted value",
+			input: []string{"--format", "yaml"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: "yaml",
+		},
+		{
+			name:  "Ignores subsequent tokens after value",
+			input: []string{"-f", "json", "jkl", "mno"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{"jkl", "mno"},
+			},
+			expectedValue: "json",
+		},
+		{
+			name:  "Ignores different short name reference",
+			input: []string{"-g"},
+			expectedResult: result{
+				parsed:    false,
+				remaining: []string{"-g"},
+			},
+		},
+		{
+			name:  "Ignores different long name reference",
+			input: []string{"--formats=json"},
+			expectedResult: result{
+				parsed:    false,
+				remaining: []string{"--formats=json"},
+			},
+		},
+		{
+			name:  "Returns error for short name reference without value",
+			input: []string{"-f"},
+			expectedResult: result{
+				err: ErrMissingOptionValue,
+			},
+		},
+		{
+			name:  "Returns error for long name reference without value",
+			input: []string{"--format"},
+			expectedResult: result{
+				err: ErrMissingOptionValue,
+			},
+		},
+		{
+			name:  "Returns error for value that is not accepted",
+			input: []string{"--format", "xml"},
+			expectedResult: result{
+				err: ErrInvalidOptionValue,
+			},
+		},
+		{
+			name:  "Returns error for empty value",
+			input: []string{"--format="},
+			expectedResult: result{
+				err: ErrInvalidOptionValue,
+			},
+		},
+		{
+			name:  "Returns error for value with different case",
+			input: []string{"-fJSON"},
+			expectedResult: result{
+				err: ErrInvalidOptionValue,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+			underTest.Value = ""
+
+			parsed, remaining, err := underTest.Parse(tt.input)
+
+			if tt.expectedResult.err != nil {
+				if !errors.Is(err, tt.expectedResult.err) {
+					t.Errorf("expected err='%v'; got '%v'", tt.expectedResult.err, err)
+					t.FailNow()
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if parsed != tt.expectedResult.parsed {
+				t.Errorf("expected parsed='%t'; got '%t'", tt.expectedResult.parsed, parsed)
+			}
+
+			if len(remaining) != len(tt.expectedResult.remaining) {
+				t.Errorf("expected '%+v'; got '%v'", tt.expectedResult.remaining, remaining)
+				t.FailNow()
+			}
+			for i := range tt.expectedResult.remaining {
+				if remaining[i] != tt.expectedResult.remaining[i] {
+					t.Errorf("expected '%+v'; got '%v'", tt.expectedResult.remaining, remaining)
+					t.FailNow()
+				}
+			}
+
+			if underTest.Value != tt.expectedValue {
+				t.Errorf("expected value='%s'; got '%s'", tt.expectedValue, underTest.Value)
+			}
+		})
+	}
+}
@@ -15,6 +15,10 @@ var ErrUnknownOption error = errors.New("ErrUnknownOption")
 // itself with an invalid value for the option.
 var ErrInvalidOptionValue error = errors.New("ErrInvalidOptionValue")
 
+// ErrMissingOptionValue is returned when Option.Parse encounters a reference to itself that is not
+// followed by the value the option requires.
+var ErrMissingOptionValue error = errors.New("ErrMissingOptionValue")
+
 // NewInvalidOptionValue returns a new error describing an invalid value for a CLI option. The
 // returned value will cause errors.Is to return true when ErrInvalidOptionValue is the target.
 func NewInvalidOptionValue(option, value string) error {
@@ -16,6 +16,11 @@ type OptionName struct {
 	ShortName rune
 }
 
+// Name returns the names the option may be addressed by.
+func (n OptionName) Name() OptionName {
+	return n
+}
+
 // A FlagOpt represents an option that contains a bool value.
 type FlagOpt struct {
 
@@ -89,6 +94,8 @@ func (f *FlagOpt) parseNoPrefix(args []string) (bool, []string, error) {
 		Value: true,
 	}
 	parsed, remaining, err := noOpt.parseLong(args, false)
-	f.Value = !noOpt.Value
+	if parsed {
+		f.Value = !noOpt.Value
+	}
 	return parsed, remaining, err
 }
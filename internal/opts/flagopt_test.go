@@ -146,6 +146,16 @@ func TestFlagOpt(t *testing.T) {
 			},
 			expectedValue: false,
 		},
+		{
+			name:          "Preserves value for different longname reference",
+			startingValue: true,
+			input:         []string{"--indent"},
+			expectedResult: result{
+				parsed:    false,
+				remaining: []string{"--indent"},
+			},
+			expectedValue: true,
+		},
 		{
 			name:          "Returns error for equals with non-bool value",
 			startingValue: false,
@@ -186,6 +196,10 @@ func TestFlagOpt(t *testing.T) {
 					t.FailNow()
 				}
 			}
+
+			if underTest.Value != tt.expectedValue {
+				t.Errorf("expected value='%t'; got '%t'", tt.expectedValue, underTest.Value)
+			}
 		})
 	}
 }
@@ -0,0 +1,28 @@
+package opts
+
+import (
+	"strconv"
+)
+
+// An IntOpt represents an option that contains an int value.
+type IntOpt struct {
+
+	// The name(s) of the option.
+	OptionName
+
+	// The value of the option.
+	Value int
+}
+
+func (i *IntOpt) Parse(args []string) (bool, []string, error) {
+	parsed, value, remaining, err := parseValue(i.OptionName, args)
+	if err != nil || !parsed {
+		return false, remaining, err
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return false, nil, NewInvalidOptionValueHelpText(i.LongName, value, "must be an integer")
+	}
+	i.Value = n
+	return true, remaining, nil
+}
@@ -0,0 +1,128 @@
+package opts
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIntOpt(t *testing.T) {
+
+	underTest := IntOpt{
+		OptionName: OptionName{
+			ShortName: 'j',
+			LongName:  "jobs",
+		},
+	}
+
+	type result struct {
+		parsed    bool
+		remaining []string
+		err       error
+	}
+
+	tests := []struct {
+		name           string
+		input          []string
+		expectedResult result
+		expectedValue  int
+	}{
+		{
+			name:  "Parses short name reference with separate value",
+			input: []string{"-j", "4"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: 4,
+		},
+		{
+			name:  "Parses short name reference with attached value",
+			input: []string{"-j4"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: 4,
+		},
+		{
+			name:  "Parses long name reference with equals",
+			input: []string{"--jobs=4"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: 4,
+		},
+		{
+			name:  "Parses negative value",
+			input: []string{"--jobs", "-4"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: -4,
+		},
+		{
+			name:  "Ignores different long name reference",
+			input: []string{"--job=4"},
+			expectedResult: result{
+				parsed:    false,
+				remaining: []string{"--job=4"},
+			},
+		},
+		{
+			name:  "Returns error for non-integer value",
+			input: []string{"--jobs=four"},
+			expectedResult: result{
+				err: ErrInvalidOptionValue,
+			},
+		},
+		{
+			name:  "Returns error for missing value",
+			input: []string{"-j"},
+			expectedResult: result{
+				err: ErrMissingOptionValue,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+			underTest.Value = 0
+
+			parsed, remaining, err := underTest.Parse(tt.input)
+
+			if tt.expectedResult.err != nil {
+				if !errors.Is(err, tt.expectedResult.err) {
+					t.Errorf("expected err='%v'; got '%v'", tt.expectedResult.err, err)
+					t.FailNow()
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if parsed != tt.expectedResult.parsed {
+				t.Errorf("expected parsed='%t'; got '%t'", tt.expectedResult.parsed, parsed)
+			}
+
+			if len(remaining) != len(tt.expectedResult.remaining) {
+				t.Errorf("expected '%+v'; got '%v'", tt.expectedResult.remaining, remaining)
+				t.FailNow()
+			}
+			for i := range tt.expectedResult.remaining {
+				if remaining[i] != tt.expectedResult.remaining[i] {
+					t.Errorf("expected '%+v'; got '%v'", tt.expectedResult.remaining, remaining)
+					t.FailNow()
+				}
+			}
+
+			if underTest.Value != tt.expectedValue {
+				t.Errorf("expected value='%d'; got '%d'", tt.expectedValue, underTest.Value)
+			}
+		})
+	}
+}
@@ -22,6 +22,15 @@ type Option interface {
 	Parse(args []string) (bool, []string, error)
 }
 
+// A NamedOption is an Option that can report the names it may be addressed by. The option types in
+// this package implement NamedOption by embedding an OptionName.
+type NamedOption interface {
+	Option
+
+	// Name returns the names the option may be addressed by.
+	Name() OptionName
+}
+
 // Parse parses args using the values of options until the first non-option argument is encountered
 // or an error occurs.
 func Parse(args []string, options []Option) ([]string, error) {
@@ -44,6 +53,32 @@ func Parse(args []string, options []Option) ([]string, error) {
 	return []string{}, nil
 }
 
+// ParseInterleaved parses args using the values of options like Parse except that options may
+// follow non-option arguments, which are collected and returned in the order they were given. Every
+// argument after the first "--" is a non-option argument.
+func ParseInterleaved(args []string, options []Option) ([]string, error) {
+	positional := []string{}
+	for len(args) > 0 {
+		if isEndOfOptionsDelimiter(args[0]) {
+			return append(positional, args[1:]...), nil
+		}
+		if !isOptionRef(args[0]) {
+			positional = append(positional, args[0])
+			args = args[1:]
+			continue
+		}
+		parsed, remaining, err := parseNextOption(args, options)
+		if err != nil {
+			return nil, err
+		}
+		if !parsed {
+			return nil, tyers.Errorf(ErrUnknownOption, "unknown option '%s'", args[0])
+		}
+		args = remaining
+	}
+	return positional, nil
+}
+
 func isEndOfOptionsDelimiter(token string) bool {
 	// https://pubs.opengroup.org/onlinepubs/9699919799/basedefs/V1_chap12.html#tag_12_02
 	// Guideline 10
@@ -2,6 +2,7 @@ package opts
 
 import (
 	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -94,6 +95,106 @@ func TestParse(t *testing.T) {
 	})
 }
 
+func TestParseInterleaved(t *testing.T) {
+
+	tests := []struct {
+		name               string
+		args               []string
+		expectedRemaining  []string
+		expectedAll        bool
+		expectedJobs       int
+		expectedExclusions []string
+	}{
+		{
+			name:              "Parses options after positional arguments",
+			args:              []string{"src", "--jobs", "4", "work", "--all"},
+			expectedRemaining: []string{"src", "work"},
+			expectedAll:       true,
+			expectedJobs:      4,
+		},
+		{
+			name:              "Treats every argument after -- as positional",
+			args:              []string{"src", "--", "--all", "--", "-j4"},
+			expectedRemaining: []string{"src", "--all", "--", "-j4"},
+		},
+		{
+			name:               "Parses -- as the value of an option",
+			args:               []string{"--exclude", "--", "src", "--all"},
+			expectedRemaining:  []string{"src"},
+			expectedAll:        true,
+			expectedExclusions: []string{"--"},
+		},
+		{
+			name:              "Parses clustered short flags ending with an attached value",
+			args:              []string{"src", "-aj4"},
+			expectedRemaining: []string{"src"},
+			expectedAll:       true,
+			expectedJobs:      4,
+		},
+		{
+			name:              "Parses clustered short flags ending with a separate value",
+			args:              []string{"-aj", "4", "src"},
+			expectedRemaining: []string{"src"},
+			expectedAll:       true,
+			expectedJobs:      4,
+		},
+		{
+			name:              "Returns empty remaining args when there are no positional arguments",
+			args:              []string{"-a"},
+			expectedRemaining: []string{},
+			expectedAll:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all := &FlagOpt{OptionName: OptionName{LongName: "all", ShortName: 'a'}}
+			jobs := &IntOpt{OptionName: OptionName{LongName: "jobs", ShortName: 'j'}}
+			exclude := &StringSliceOpt{OptionName: OptionName{LongName: "exclude"}}
+			remaining, err := ParseInterleaved(tt.args, []Option{all, jobs, exclude})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(remaining, tt.expectedRemaining) {
+				t.Errorf("expected remaining %#v; got %#v", tt.expectedRemaining, remaining)
+			}
+			if all.Value != tt.expectedAll {
+				t.Errorf("expected all %v; got %v", tt.expectedAll, all.Value)
+			}
+			if jobs.Value != tt.expectedJobs {
+				t.Errorf("expected jobs %d; got %d", tt.expectedJobs, jobs.Value)
+			}
+			if !reflect.DeepEqual(exclude.Value, tt.expectedExclusions) {
+				t.Errorf("expected exclusions %#v; got %#v", tt.expectedExclusions, exclude.Value)
+			}
+		})
+	}
+
+	t.Run("Returns ErrUnknownOption for reference to unknown option after positional argument", func(t *testing.T) {
+		_, err := ParseInterleaved([]string{"src", "--foo"}, []Option{})
+		if !errors.Is(err, ErrUnknownOption) {
+			t.Errorf("expected '%v'; got '%v'", ErrUnknownOption, err)
+		}
+	})
+}
+
+func TestNamedOption(t *testing.T) {
+	name := OptionName{LongName: "foo", ShortName: 'f'}
+	options := []NamedOption{
+		&DurationOpt{OptionName: name},
+		&EnumOpt{OptionName: name},
+		&FlagOpt{OptionName: name},
+		&IntOpt{OptionName: name},
+		&StringOpt{OptionName: name},
+		&StringSliceOpt{OptionName: name},
+	}
+	for _, option := range options {
+		if actual := option.Name(); actual != name {
+			t.Errorf("expected %+v; got %+v", name, actual)
+		}
+	}
+}
+
 type mockOption struct {
 	parsed    bool
 	remaining []string
@@ -0,0 +1,63 @@
+package opts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ttd2089/tyers"
+)
+
+// A StringOpt represents an option that contains a string value.
+type StringOpt struct {
+
+	// The name(s) of the option.
+	OptionName
+
+	// The value of the option.
+	Value string
+}
+
+func (s *StringOpt) Parse(args []string) (bool, []string, error) {
+	parsed, value, remaining, err := parseValue(s.OptionName, args)
+	if err != nil || !parsed {
+		return false, remaining, err
+	}
+	s.Value = value
+	return true, remaining, nil
+}
+
+// parseValue attempts to consume a reference to an option that requires a value from the first
+// value(s) of args. The value may be attached to the reference (-nvalue or --name=value) or be
+// supplied as the next value of args (-n value or --name value).
+func parseValue(name OptionName, args []string) (bool, string, []string, error) {
+	if len(args) == 0 {
+		return false, "", args, nil
+	}
+	if name.ShortName != 0 {
+		shortNameRef := fmt.Sprintf("-%c", name.ShortName)
+		if args[0] == shortNameRef {
+			return parseSeparateValue(shortNameRef, args)
+		}
+		if strings.HasPrefix(args[0], shortNameRef) {
+			return true, strings.TrimPrefix(args[0], shortNameRef), args[1:], nil
+		}
+	}
+	if name.LongName != "" {
+		longNameRef := fmt.Sprintf("--%s", name.LongName)
+		if args[0] == longNameRef {
+			return parseSeparateValue(longNameRef, args)
+		}
+		refWithEquals := fmt.Sprintf("%s=", longNameRef)
+		if strings.HasPrefix(args[0], refWithEquals) {
+			return true, strings.TrimPrefix(args[0], refWithEquals), args[1:], nil
+		}
+	}
+	return false, "", args, nil
+}
+
+func parseSeparateValue(ref string, args []string) (bool, string, []string, error) {
+	if len(args) < 2 {
+		return false, "", nil, tyers.Errorf(ErrMissingOptionValue, "option '%s' requires a value", ref)
+	}
+	return true, args[1], args[2:], nil
+}
@@ -0,0 +1,20 @@
+package opts
+
+// A StringSliceOpt represents an option that may be repeated to collect several string values.
+type StringSliceOpt struct {
+
+	// The name(s) of the option.
+	OptionName
+
+	// The values of the option in the order they were given.
+	Value []string
+}
+
+func (s *StringSliceOpt) Parse(args []string) (bool, []string, error) {
+	parsed, value, remaining, err := parseValue(s.OptionName, args)
+	if err != nil || !parsed {
+		return false, remaining, err
+	}
+	s.Value = append(s.Value, value)
+	return true, remaining, nil
+}
@@ -0,0 +1,157 @@
+package opts
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStringSliceOpt(t *testing.T) {
+
+	underTest := StringSliceOpt{
+		OptionName: OptionName{
+			ShortName: 'x',
+			LongName:  "exclude",
+		},
+	}
+
+	type result struct {
+		parsed    bool
+		remaining []string
+		err       error
+	}
+
+	tests := []struct {
+		name           string
+		input          []string
+		expectedResult result
+		expectedValue  []string
+	}{
+		{
+			name:  "Parses short name reference with separate value",
+			input: []string{"-x", "vendor"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: []string{"vendor"},
+		},
+		{
+			name:  "Parses short name reference with attached value",
+			input: []string{"-xvendor"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: []string{"vendor"},
+		},
+		{
+			name:  "Parses long name reference with separate value",
+			input: []string{"--exclude", "vendor"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: []string{"vendor"},
+		},
+		{
+			name:  "Parses long name reference with equals",
+			input: []string{"--exclude=vendor"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{},
+			},
+			expectedValue: []string{"vendor"},
+		},
+		{
+			name:  "Parses only the first reference",
+			input: []string{"-x", "vendor", "-x", "build"},
+			expectedResult: result{
+				parsed:    true,
+				remaining: []string{"-x", "build"},
+			},
+			expectedValue: []string{"vendor"},
+		},
+		{
+			name:  "Ignores different long name reference",
+			input: []string{"--excludes=vendor"},
+			expectedResult: result{
+				parsed:    false,
+				remaining: []string{"--excludes=vendor"},
+			},
+		},
+		{
+			name:  "Returns error for long name reference without value",
+			input: []string{"--exclude"},
+			expectedResult: result{
+				err: ErrMissingOptionValue,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+
+		t.Run(tt.name, func(t *testing.T) {
+			underTest.Value = nil
+
+			parsed, remaining, err := underTest.Parse(tt.input)
+
+			if tt.expectedResult.err != nil {
+				if !errors.Is(err, tt.expectedResult.err) {
+					t.Errorf("expected err='%v'; got '%v'", tt.expectedResult.err, err)
+					t.FailNow()
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if parsed != tt.expectedResult.parsed {
+				t.Errorf("expected parsed='%t'; got '%t'", tt.expectedResult.parsed, parsed)
+			}
+
+			if len(remaining) != len(tt.expectedResult.remaining) {
+				t.Errorf("expected '%+v'; got '%v'", tt.expectedResult.remaining, remaining)
+				t.FailNow()
+			}
+			for i := range tt.expectedResult.remaining {
+				if remaining[i] != tt.expectedResult.remaining[i] {
+					t.Errorf("expected '%+v'; got '%v'", tt.expectedResult.remaining, remaining)
+					t.FailNow()
+				}
+			}
+
+			if len(underTest.Value) != len(tt.expectedValue) {
+				t.Errorf("expected value='%v'; got '%v'", tt.expectedValue, underTest.Value)
+				t.FailNow()
+			}
+			for i := range tt.expectedValue {
+				if underTest.Value[i] != tt.expectedValue[i] {
+					t.Errorf("expected value='%v'; got '%v'", tt.expectedValue, underTest.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestStringSliceOptCollectsRepeatedReferences(t *testing.T) {
+
+	underTest := StringSliceOpt{
+		OptionName: OptionName{
+			ShortName: 'x',
+			LongName:  "exclude",
+		},
+	}
+
+	remaining, err := Parse([]string{"-x", "vendor", "--exclude=build", "dir"}, []Option{&underTest})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0] != "dir" {
+		t.Errorf("expected '[dir]'; got '%v'", remaining)
+	}
+	if len(underTest.Value) != 2 || underTest.Value[0] != "vendor" || underTest.Value[1] != "build" {
+		t.Errorf("expected value='[vendor build]'; got '%v'", underTest.Value)
+	}
+}